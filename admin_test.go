@@ -0,0 +1,190 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/admin"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// Returns a test server for the admin handler of a new [logger.MultiLogger]
+// with a stopped default logger and a JSON logger with key "json".
+func newAdminServer(t *testing.T) (*logger.MultiLogger, *httptest.Server) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	if err := l.Register("json", loggers.NewJSONLogger(levels.Info, io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+	handler := admin.NewHandler(l)
+	server := httptest.NewServer(http.StripPrefix("/loggers", handler))
+	t.Cleanup(func() {
+		server.Close()
+		handler.Close()
+	})
+	return l, server
+}
+
+func adminRequest(t *testing.T, method, url, body string, result any) int {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	if result != nil {
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(result))
+	}
+	return response.StatusCode
+}
+
+func TestAdminList(t *testing.T) {
+	_, server := newAdminServer(t)
+
+	var states []admin.LoggerState
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodGet, server.URL+"/loggers/", "", &states))
+	assert.Equal(t, []admin.LoggerState{
+		{Key: "", Type: "ConsoleLogger", Level: levels.Info, Stopped: true},
+		{Key: "json", Type: "JSONLogger", Level: levels.Info},
+	}, states)
+
+	var state admin.LoggerState
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodGet, server.URL+"/loggers/default", "", &state))
+	assert.Equal(t, "ConsoleLogger", state.Type)
+
+	var failure map[string]string
+	assert.Equal(t, http.StatusNotFound, adminRequest(t, http.MethodGet, server.URL+"/loggers/missing", "", &failure))
+	assert.Contains(t, failure["error"], `"missing"`)
+	assert.Equal(t, http.StatusMethodNotAllowed, adminRequest(t, http.MethodDelete, server.URL+"/loggers/json", "", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, adminRequest(t, http.MethodPut, server.URL+"/loggers/", "{}", nil))
+}
+
+func TestAdminUpdate(t *testing.T) {
+	l, server := newAdminServer(t)
+
+	var state admin.LoggerState
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "error", "stopped": true}`, &state))
+	assert.Equal(t, admin.LoggerState{Key: "json", Type: "JSONLogger", Level: levels.Error, Stopped: true}, state)
+	assert.Equal(t, levels.Error, l.Get("json").GetLevel())
+	assert.False(t, l.Get("json").(*loggers.JSONLogger).IsLogAllowed(levels.Fatal))
+
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/default", `{"stopped": false}`, &state))
+	assert.False(t, state.Stopped)
+	assert.True(t, l.DefaultLogger().(*loggers.ConsoleLogger).IsLogAllowed(levels.Info))
+
+	var failure map[string]string
+	assert.Equal(t, http.StatusBadRequest, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "loud"}`, &failure))
+	assert.Contains(t, failure["error"], `"loud"`)
+	assert.Equal(t, http.StatusBadRequest, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"levle": "debug"}`, &failure))
+	assert.Equal(t, http.StatusBadRequest, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"revert_after": "1m"}`, &failure))
+	assert.Contains(t, failure["error"], "requires level")
+	assert.Equal(t, http.StatusBadRequest, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "debug", "revert_after": "soon"}`, &failure))
+	assert.Equal(t, http.StatusNotFound, adminRequest(t, http.MethodPut, server.URL+"/loggers/missing", `{"level": "debug"}`, &failure))
+	assert.Equal(t, levels.Error, l.Get("json").GetLevel())
+}
+
+func TestAdminDefaultKeyClash(t *testing.T) {
+	l, server := newAdminServer(t)
+	if err := l.Register(admin.DefaultKey, loggers.NewJSONLogger(levels.Info, io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+
+	var failure map[string]string
+	assert.Equal(t, http.StatusConflict, adminRequest(t, http.MethodGet, server.URL+"/loggers/default", "", &failure))
+	assert.Contains(t, failure["error"], "reserved for the default logger")
+	assert.Equal(t, http.StatusConflict, adminRequest(t, http.MethodPut, server.URL+"/loggers/default", `{"level": "debug"}`, &failure))
+	assert.Equal(t, levels.Info, l.DefaultLogger().GetLevel())
+	assert.Equal(t, levels.Info, l.Get(admin.DefaultKey).GetLevel())
+}
+
+func TestAdminRevertLevel(t *testing.T) {
+	l, server := newAdminServer(t)
+
+	var state admin.LoggerState
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "debug", "revert_after": "1h"}`, &state))
+	assert.Equal(t, levels.Debug, state.Level)
+	if assert.NotNil(t, state.RevertLevel) && assert.NotNil(t, state.RevertAt) {
+		assert.Equal(t, levels.Info, *state.RevertLevel)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *state.RevertAt, time.Minute)
+	}
+
+	// The second temporary level reverts to the level before the first one.
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "trace", "revert_after": "50ms"}`, &state))
+	assert.Equal(t, levels.Info, *state.RevertLevel)
+	assert.Eventually(t, func() bool {
+		state = admin.LoggerState{}
+		adminRequest(t, http.MethodGet, server.URL+"/loggers/json", "", &state)
+		return state.Level == levels.Info
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Nil(t, state.RevertLevel)
+
+	// A level set without revert_after cancels the pending revert.
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "debug", "revert_after": "50ms"}`, &state))
+	state = admin.LoggerState{}
+	assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", `{"level": "warning"}`, &state))
+	assert.Nil(t, state.RevertLevel)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, levels.Warning, l.Get("json").GetLevel())
+}
+
+// Run with -race to check that the levels and the stopped states
+// can be changed while other goroutines are logging.
+func TestAdminUpdateWhileLogging(t *testing.T) {
+	l, server := newAdminServer(t)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					l.DebugKV("Debug message", "user", 42)
+					l.Info("Info message")
+				}
+			}
+		}()
+	}
+	for i, body := range []string{`{"level": "debug"}`, `{"stopped": true}`, `{"level": "error", "stopped": false}`, `{"level": "trace"}`} {
+		var state admin.LoggerState
+		assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/json", body, &state), i)
+		// The default logger stays stopped, so the messages are not printed on the console.
+		assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodPut, server.URL+"/loggers/default", `{"level": "debug"}`, &state), i)
+	}
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, levels.Trace, l.Get("json").GetLevel())
+	assert.False(t, l.Get("json").(*loggers.JSONLogger).IsStopped())
+}
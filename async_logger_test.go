@@ -0,0 +1,227 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// gateLogger records the messages, but each message
+// waits until the gate is opened.
+type gateLogger struct {
+	recordingLogger
+	started     chan struct{}
+	startedOnce sync.Once
+	gate        chan struct{}
+}
+
+func newGateLogger() *gateLogger {
+	return &gateLogger{
+		recordingLogger: recordingLogger{LoggerType: loggers.LoggerType{Level: levels.All}},
+		started:         make(chan struct{}),
+		gate:            make(chan struct{}),
+	}
+}
+
+func (logger *gateLogger) Log(level levels.LogLevel, arg any) {
+	logger.LogF(level, "%v", arg)
+}
+
+func (logger *gateLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
+	logger.startedOnce.Do(func() { close(logger.started) })
+	<-logger.gate
+	logger.recordingLogger.LogF(level, format, args...)
+}
+
+// Logs the first message and waits until the background goroutine
+// is blocked printing it, so the queue is empty.
+func blockAsyncLogger(t *testing.T, asyncLogger *loggers.AsyncLogger, gate *gateLogger) {
+	asyncLogger.Log(levels.Info, "Message 1")
+	select {
+	case <-gate.started:
+	case <-time.After(time.Second):
+		t.Fatal("the first message was not printed")
+	}
+}
+
+func TestAsyncLoggerBlock(t *testing.T) {
+	r := newRecordingLogger()
+	asyncLogger := loggers.NewAsyncLogger(r, loggers.AsyncOptions{QueueSize: 2})
+
+	for i := 1; i <= 100; i++ {
+		asyncLogger.LogF(levels.Info, "Message %d", i)
+	}
+	asyncLogger.Stop()
+
+	messages := r.Messages()
+	assert.Len(t, messages, 100)
+	assert.Equal(t, "Message 1", messages[0])
+	assert.Equal(t, "Message 100", messages[99])
+	assert.Equal(t, uint64(0), asyncLogger.Dropped())
+}
+
+func TestAsyncLoggerDropNewest(t *testing.T) {
+	gate := newGateLogger()
+	asyncLogger := loggers.NewAsyncLogger(gate, loggers.AsyncOptions{QueueSize: 2, Overflow: loggers.OverflowDropNewest})
+	blockAsyncLogger(t, asyncLogger, gate)
+
+	for i := 2; i <= 5; i++ {
+		asyncLogger.LogF(levels.Info, "Message %d", i)
+	}
+	assert.Equal(t, uint64(2), asyncLogger.Dropped())
+	close(gate.gate)
+	asyncLogger.Stop()
+
+	assert.Equal(t, []string{"Message 1", "Message 2", "Message 3"}, gate.Messages())
+}
+
+func TestAsyncLoggerDropOldest(t *testing.T) {
+	gate := newGateLogger()
+	asyncLogger := loggers.NewAsyncLogger(gate, loggers.AsyncOptions{QueueSize: 2, Overflow: loggers.OverflowDropOldest})
+	blockAsyncLogger(t, asyncLogger, gate)
+
+	for i := 2; i <= 5; i++ {
+		asyncLogger.LogF(levels.Info, "Message %d", i)
+	}
+	assert.Equal(t, uint64(2), asyncLogger.Dropped())
+	close(gate.gate)
+	asyncLogger.Stop()
+
+	assert.Equal(t, []string{"Message 1", "Message 4", "Message 5"}, gate.Messages())
+}
+
+func TestAsyncLoggerDropBelowLevel(t *testing.T) {
+	gate := newGateLogger()
+	asyncLogger := loggers.NewAsyncLogger(gate, loggers.AsyncOptions{
+		QueueSize: 2,
+		Overflow:  loggers.OverflowDropBelowLevel,
+		DropLevel: levels.Error,
+	})
+	blockAsyncLogger(t, asyncLogger, gate)
+
+	asyncLogger.Log(levels.Info, "Message 2")
+	asyncLogger.Log(levels.Info, "Message 3")
+	asyncLogger.Log(levels.Warning, "Dropped message")
+
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		asyncLogger.Log(levels.Error, "Error message")
+	}()
+	select {
+	case <-logged:
+		t.Fatal("the error message must wait for space in the queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(gate.gate)
+	<-logged
+	asyncLogger.Stop()
+
+	assert.Equal(t, uint64(1), asyncLogger.Dropped())
+	assert.Equal(t, []string{"Message 1", "Message 2", "Message 3", "Error message"}, gate.Messages())
+}
+
+func TestAsyncLoggerFlushAndRestart(t *testing.T) {
+	r := newRecordingLogger()
+	asyncLogger := loggers.NewAsyncLogger(r, loggers.AsyncOptions{})
+
+	asyncLogger.Log(levels.Debug, "Message 1")
+	asyncLogger.LogKV(levels.Info, "Message 2", loggers.F("user", 42))
+	assert.NoError(t, asyncLogger.Flush())
+	assert.Equal(t, []string{"Message 1", "Message 2 user=42"}, r.Messages())
+
+	asyncLogger.Stop()
+	asyncLogger.Log(levels.Info, "Stopped message")
+	asyncLogger.Start()
+	asyncLogger.Log(levels.Info, "Message 3")
+	assert.NoError(t, asyncLogger.Close())
+	assert.Equal(t, []string{"Message 1", "Message 2 user=42", "Message 3"}, r.Messages())
+}
+
+func TestAsyncLoggerFormatsWhenQueued(t *testing.T) {
+	gate := newGateLogger()
+	asyncLogger := loggers.NewAsyncLogger(gate, loggers.AsyncOptions{QueueSize: 4})
+	blockAsyncLogger(t, asyncLogger, gate)
+
+	counts := map[string]int{"a": 1}
+	asyncLogger.LogF(levels.Info, "Counts %v", counts)
+	asyncLogger.Log(levels.Info, counts)
+	asyncLogger.Log(levels.Error, fmt.Errorf("Error %d", counts["a"]))
+	asyncLogger.LogKV(levels.Info, "Fields", loggers.F("counts", counts))
+	// The arguments are changed while the messages are queued.
+	counts["a"] = 2
+
+	close(gate.gate)
+	assert.NoError(t, asyncLogger.Close())
+	assert.Equal(t, []string{"Message 1", "Counts map[a:1]", "map[a:1]", "Error 1", "Fields counts=map[a:1]"}, gate.Messages())
+}
+
+// Run with -race to check that the fields can be changed after the call.
+func TestAsyncLoggerFieldsFormatsWhenQueued(t *testing.T) {
+	var buf bytes.Buffer
+	asyncLogger := loggers.NewAsyncLogger(loggers.NewJSONLogger(levels.Info, &buf), loggers.AsyncOptions{})
+	counts := map[string]int{"a": 1}
+	asyncLogger.LogKV(levels.Info, "Fields", loggers.F("counts", counts), loggers.F("user", 42))
+	counts["a"] = 2
+	assert.NoError(t, asyncLogger.Close())
+
+	entries := decodeJSONLines(t, buf.String())
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, map[string]any{"a": float64(1)}, entries[0]["counts"])
+		assert.Equal(t, float64(42), entries[0]["user"])
+	}
+}
+
+func TestAsyncLoggerRegistered(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	r := newRecordingLogger()
+	asyncLogger := loggers.NewAsyncLogger(r, loggers.AsyncOptions{QueueSize: 16})
+	asyncLogger.SetLevel(levels.Warning)
+	key := "async_key"
+	err := logger.RegisterLogger(key, asyncLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				logger.Info("Skipped message")
+				logger.WarningKV(fmt.Sprintf("Message %d", i), "goroutine", g)
+			}
+		}(g)
+	}
+	wg.Wait()
+	asyncLogger.Stop()
+
+	assert.Equal(t, levels.Warning, r.GetLevel())
+	assert.Len(t, r.Messages(), 100)
+}
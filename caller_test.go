@@ -0,0 +1,124 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// Returns the location of the line, which calls this function, shifted by "offset" lines.
+func callerLine(offset int) string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line+offset)
+}
+
+func TestCallerFile(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	l.SetCallerMode(logger.CallerFile)
+	reqLog := l.With("request_id", 7)
+
+	var expected []string
+	expected = append(expected, "Message caller="+callerLine(1))
+	l.Info("Message")
+	expected = append(expected, "Message 2 caller="+callerLine(1))
+	l.WarningF("Message %d", 2)
+	expected = append(expected, "Message 3 caller="+callerLine(1)+" user=42")
+	l.ErrorKV("Message 3", "user", 42)
+	expected = append(expected, "Message 4 caller="+callerLine(1)+" request_id=7")
+	reqLog.Info("Message 4")
+	expected = append(expected, "Message 5 caller="+callerLine(1)+" request_id=7")
+	reqLog.InfoCtx(context.Background(), "Message 5")
+
+	assert.Equal(t, expected, r.Messages())
+}
+
+func TestCallerFunction(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	l.SetCallerMode(logger.CallerFunction)
+	l.Info("Message")
+	l.SetCallerMode(logger.CallerNone)
+	l.Info("Message 2")
+	assert.Equal(t, []string{
+		"Message caller=github.com/takecontrolsoft/go_multi_log.TestCallerFunction",
+		"Message 2",
+	}, r.Messages())
+}
+
+func TestCallerPackageFunctions(t *testing.T) {
+	logger.SetCallerMode(logger.CallerFile)
+	defer logger.SetCallerMode(logger.CallerNone)
+
+	var expected []string
+	content := readConsole(func() {
+		expected = append(expected, callerLine(1)+": INFO: [Message]")
+		logger.Info("Message")
+		expected = append(expected, callerLine(1)+": WARNING: [Message 2] user=42")
+		logger.WarningKV("Message 2", "user", 42)
+		expected = append(expected, callerLine(1)+": ERROR: [Message 3]")
+		logger.ErrorCtx(context.Background(), "Message 3")
+	})
+	for _, line := range expected {
+		assert.Contains(t, content, line)
+	}
+}
+
+func TestCallerFileLogger(t *testing.T) {
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+	}
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	l.Register("file", loggers.NewFileLogger(levels.Info, "", fileOptions))
+	l.SetCallerMode(logger.CallerFile)
+	expected := callerLine(1) + ": INFO: [Message] user=42"
+	l.InfoKV("Message", "user", 42)
+	l.Unregister("file")
+
+	content := removeLogFiles(t, fileOptions)
+	assert.Contains(t, content, expected)
+}
+
+func TestCallerStructuredLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	l.Register("json", loggers.NewJSONLogger(levels.Info, &buf))
+	l.SetCallerMode(logger.CallerFile)
+
+	expected := []string{callerLine(1)}
+	l.Info("Message")
+	expected = append(expected, callerLine(1))
+	slog.New(l.SlogHandler()).Info("Slog message")
+
+	lines := decodeJSONLines(t, buf.String())
+	assert.Len(t, lines, 2)
+	for i, line := range lines {
+		assert.Equal(t, expected[i], line[loggers.CallerKey])
+	}
+}
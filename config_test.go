@@ -0,0 +1,257 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+const yamlConfig = `
+level: error
+disable_default: true
+loggers:
+  app_json:
+    type: file
+    level: debug
+    format: json
+    file:
+      directory: %[1]s
+      prefix: app
+      extension: .json
+      max_size: 1048576
+      rollover: daily
+      max_age: 168h
+  app_template:
+    type: file
+    level: warn
+    format: "{level} {message} {fields}"
+    file:
+      directory: %[1]s
+      prefix: template
+      extension: .log
+  console:
+    type: console
+    format: logfmt
+`
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigureFromYAMLFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfigFile(t, "log.yaml", fmt.Sprintf(yamlConfig, dir))
+	l := logger.New()
+	assert.NoError(t, l.ConfigureFromFile(path))
+
+	assert.Equal(t, levels.Error, l.DefaultLogger().GetLevel())
+	assert.False(t, l.DefaultLogger().(*loggers.ConsoleLogger).IsLogAllowed(levels.Fatal))
+	assert.IsType(t, &loggers.ConsoleLogger{}, l.Get("console"))
+	assert.Equal(t, levels.Info, l.Get("console").GetLevel())
+	jsonLogger := l.Get("app_json").(*loggers.FileLogger)
+	assert.Equal(t, levels.Debug, jsonLogger.GetLevel())
+	assert.Equal(t, loggers.RolloverDaily, jsonLogger.FileOptions.Rollover)
+	assert.Equal(t, 168*time.Hour, jsonLogger.FileOptions.MaxAge)
+	assert.Equal(t, int64(1048576), jsonLogger.FileOptions.MaxSize)
+	l.Get("console").Stop()
+
+	l.DebugKV("Debug message", "user", 42)
+	l.WarningKV("Warning message", "user", 7)
+	assert.NoError(t, l.Shutdown(context.Background()))
+
+	var jsonLines []map[string]any
+	for _, name := range listFiles(t, dir) {
+		content := readFileContent(t, filepath.Join(dir, name))
+		if strings.HasPrefix(name, "template") {
+			assert.Equal(t, "WARNING Warning message user=7\n", content)
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+			var entry map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+			jsonLines = append(jsonLines, entry)
+		}
+	}
+	if assert.Len(t, jsonLines, 2) {
+		assert.Equal(t, "Debug message", jsonLines[0]["msg"])
+		assert.Equal(t, "Warning message", jsonLines[1]["msg"])
+	}
+}
+
+func TestConfigureFromJSONFile(t *testing.T) {
+	path := writeConfigFile(t, "log.json", `{
+		"loggers": {
+			"pretty": {"type": "console", "level": "trace", "format": "pretty", "color": "never"},
+			"text": {"type": "console", "format": "text"}
+		}
+	}`)
+	l := logger.New()
+	assert.NoError(t, l.ConfigureFromFile(path))
+	assert.Equal(t, levels.Trace, l.Get("pretty").GetLevel())
+	assert.Equal(t, levels.Info, l.Get("text").GetLevel())
+	assert.Equal(t, levels.Info, l.DefaultLogger().GetLevel())
+	assert.True(t, l.DefaultLogger().(*loggers.ConsoleLogger).IsLogAllowed(levels.Info))
+}
+
+func TestConfigureReplacesLoggers(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	config, err := logger.ParseJSONConfig([]byte(`{"loggers": {"recording": {"type": "console", "level": "error"}}}`))
+	assert.NoError(t, err)
+	assert.NoError(t, l.Configure(config))
+	assert.NotSame(t, r, l.Get("recording"))
+	assert.Equal(t, levels.Error, l.Get("recording").GetLevel())
+}
+
+func TestConfigErrors(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	config, err := logger.ParseYAMLConfig([]byte(`
+level: loud
+loggers:
+  bad_type:
+    type: fil
+  bad_level:
+    type: console
+    level: verbose
+  bad_format:
+    type: console
+    format: xml
+  bad_color:
+    type: console
+    color: never
+  bad_file:
+    type: file
+    file:
+      rollover: weekly
+      max_age: week
+  good:
+    type: console
+`))
+	assert.NoError(t, err)
+	err = l.Configure(config)
+	if assert.Error(t, err) {
+		message := err.Error()
+		assert.Contains(t, message, `Default logger: `)
+		assert.Contains(t, message, `Logger "bad_type": Unknown type "fil"`)
+		assert.Contains(t, message, `Logger "bad_level": `)
+		assert.Contains(t, message, `"verbose"`)
+		assert.Contains(t, message, `Logger "bad_format": Unknown format "xml"`)
+		assert.Contains(t, message, `Logger "bad_color": Color is allowed only for format "pretty"`)
+		assert.Contains(t, message, `Logger "bad_file": Unknown rollover "weekly"`)
+		assert.Contains(t, message, `Option max_age is not valid`)
+		assert.NotContains(t, message, `"good"`)
+	}
+	// Nothing is registered when the configuration is not valid.
+	assert.Nil(t, l.Get("good"))
+	assert.Same(t, r, l.Get("recording"))
+	assert.Equal(t, levels.Info, l.DefaultLogger().GetLevel())
+}
+
+func TestConfigLevels(t *testing.T) {
+	config, err := logger.ParseYAMLConfig([]byte(`
+level: 4
+loggers:
+  name:
+    type: console
+    level: WARN
+  number:
+    type: console
+    level: 1
+  unset:
+    type: console
+`))
+	if assert.NoError(t, err) {
+		l := logger.New()
+		assert.NoError(t, l.Configure(config))
+		assert.Equal(t, levels.Warning, l.DefaultLogger().GetLevel())
+		assert.Equal(t, levels.Warning, l.Get("name").GetLevel())
+		assert.Equal(t, levels.Debug, l.Get("number").GetLevel())
+		assert.Equal(t, levels.Info, l.Get("unset").GetLevel())
+	}
+
+	config, err = logger.ParseJSONConfig([]byte(`{"loggers": {
+		"name": {"type": "console", "level": "trace"},
+		"number": {"type": "console", "level": 5}
+	}}`))
+	if assert.NoError(t, err) {
+		l := logger.New()
+		assert.NoError(t, l.Configure(config))
+		assert.Equal(t, levels.Trace, l.Get("name").GetLevel())
+		assert.Equal(t, levels.Error, l.Get("number").GetLevel())
+		assert.Equal(t, levels.Info, l.DefaultLogger().GetLevel())
+	}
+
+	// The invalid levels are reported with the keys of the loggers.
+	config, err = logger.ParseJSONConfig([]byte(`{"level": 7, "loggers": {"x": {"type": "console", "level": 9}}}`))
+	if assert.NoError(t, err) {
+		err = logger.New().Configure(config)
+		assert.ErrorContains(t, err, `Default logger: Unknown log level "7"`)
+		assert.ErrorContains(t, err, `Logger "x": Unknown log level "9"`)
+	}
+	_, err = logger.ParseJSONConfig([]byte(`{"level": true}`))
+	assert.ErrorContains(t, err, "Log level must be a string or a number")
+	_, err = logger.ParseYAMLConfig([]byte("level: [debug]\n"))
+	assert.ErrorContains(t, err, "Log level must be a string or a number")
+}
+
+func TestConfigPartialFileOptions(t *testing.T) {
+	dir := t.TempDir()
+	config, err := logger.ParseJSONConfig([]byte(fmt.Sprintf(`{"loggers": {
+		"file": {"type": "file", "file": {"directory": %q, "max_size": 1024}}
+	}}`, dir)))
+	assert.NoError(t, err)
+	l := logger.New()
+	assert.NoError(t, l.Configure(config))
+	fileLogger := l.Get("file").(*loggers.FileLogger)
+	assert.Equal(t, "mLog", fileLogger.FilePrefix)
+	assert.Equal(t, ".log", fileLogger.FileExtension)
+	assert.Equal(t, int64(1024), fileLogger.MaxSize)
+
+	l.DefaultLogger().Stop()
+	l.Info("Message")
+	assert.NoError(t, l.Shutdown(context.Background()))
+	names := listFiles(t, dir)
+	if assert.Len(t, names, 1) {
+		assert.Equal(t, fmt.Sprintf("mLog_%d.log", os.Getpid()), names[0])
+	}
+}
+
+func TestConfigParseErrors(t *testing.T) {
+	_, err := logger.ParseJSONConfig([]byte(`{"loggers": {"x": {"type": "console", "colour": "never"}}}`))
+	assert.ErrorContains(t, err, `unknown field "colour"`)
+	_, err = logger.ParseYAMLConfig([]byte("loggers:\n  x:\n    typ: console\n"))
+	assert.ErrorContains(t, err, "field typ not found")
+	_, err = logger.LoadConfig(writeConfigFile(t, "log.toml", ""))
+	assert.ErrorContains(t, err, "must have extension")
+	_, err = logger.LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+	assert.Error(t, logger.New().Configure(&logger.Config{Loggers: map[string]logger.LoggerConfig{"": {Type: "console"}}}))
+}
@@ -0,0 +1,99 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+type traceKey struct{}
+type userKey struct{}
+
+// Returns a context with trace id and user
+// and registers the extractors of them.
+func newTraceContext(l *logger.MultiLogger) context.Context {
+	l.RegisterContextExtractor(func(ctx context.Context) []loggers.Field {
+		if traceId, ok := ctx.Value(traceKey{}).(string); ok {
+			return []loggers.Field{loggers.F("trace_id", traceId)}
+		}
+		return nil
+	})
+	l.RegisterContextExtractor(func(ctx context.Context) []loggers.Field {
+		if user, ok := ctx.Value(userKey{}).(string); ok {
+			return []loggers.Field{loggers.F("user", user)}
+		}
+		return nil
+	})
+	ctx := context.WithValue(context.Background(), traceKey{}, "abc")
+	return context.WithValue(ctx, userKey{}, "michael")
+}
+
+func TestContextExtractors(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	ctx := newTraceContext(l)
+
+	l.InfoCtx(ctx, "Request started")
+	l.With("request_id", 7).WarningCtx(ctx, "Slow request", "latency", "2s")
+	l.ErrorCtx(context.Background(), "No values")
+	l.DebugCtx(nil, "Nil context")
+
+	assert.Equal(t, []string{
+		"Request started trace_id=abc user=michael",
+		"Slow request request_id=7 trace_id=abc user=michael latency=2s",
+		"No values",
+		"Nil context",
+	}, r.Messages())
+}
+
+func TestFromContext(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	ctx := newTraceContext(l)
+
+	assert.Same(t, logger.DefaultMultiLogger(), logger.FromContext(context.Background()))
+	assert.Same(t, logger.DefaultMultiLogger(), logger.FromContext(nil))
+	reqLog := l.With("request_id", 7)
+	ctx = logger.NewContext(ctx, reqLog)
+	assert.Same(t, reqLog, logger.FromContext(ctx))
+
+	logger.InfoCtx(ctx, "Package message", "step", 1)
+	logger.FromContext(ctx).Info("Bound message")
+	assert.Equal(t, []string{
+		"Package message request_id=7 trace_id=abc user=michael step=1",
+		"Bound message request_id=7",
+	}, r.Messages())
+}
+
+func TestFatalCtx(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	ctx := logger.NewContext(newTraceContext(l), l)
+	assert.PanicsWithValue(t, "Fatal message", func() {
+		logger.FatalCtx(ctx, "Fatal message")
+	})
+	assert.Equal(t, []string{"Fatal message trace_id=abc user=michael"}, r.Messages())
+}
+
+func TestSlogHandlerContext(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	ctx := newTraceContext(l)
+	slog.New(l.SlogHandler()).InfoContext(ctx, "Slog message", "step", 1)
+	assert.Equal(t, []string{"Slog message trace_id=abc user=michael step=1"}, r.Messages())
+}
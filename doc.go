@@ -11,12 +11,15 @@
 //
 //   - [loggers.ConsoleLogger]
 //   - [loggers.FileLogger]
+//   - [loggers.JSONLogger]
+//   - [loggers.LogfmtLogger]
+//   - [loggers.WriterLogger]
 //   - Custom logger
 //
 // # Get started
 //
 //   - Default log level is "Info", which means that only "Info", "Warning", "Error" and "Fatal" messages will be logged, but "Debug" and "Trace" messages will be skipped.
-//   - The function "Fatal" logs the messages and then calls "panic". It can be used for fatal errors and it will ensure storing the log into a file before closing the application.
+//   - The functions "Fatal", "FatalF" and "FatalKV" log the messages and then call "panic". They can be used for fatal errors and will ensure storing the log into a file before closing the application. The behavior can be changed with "SetFatalHandler".
 //
 // # Imports
 //
@@ -60,6 +63,56 @@
 //	car := car{Year: "2020"}
 //	logger.InfoF("Person: %v, Car: %v", person, car)
 //
+// - Log structured fields
+//
+// Using functions "DebugKV", "TraceKV", "InfoKV", "WarningKV", "ErrorKV", "FatalKV"
+// the message is followed by fields given as alternating keys and values or as [loggers.Field].
+//
+//	logger.InfoKV("Request done", "user", 42, "latency", time.Since(start))
+//	// INFO: [Request done] user=42 latency=1.5s
+//
+// - Child loggers with bound fields
+//
+// "With" returns a child logger, which adds its fields to each message.
+//
+//	reqLog := logger.With("request_id", id, "tenant", tenant)
+//	reqLog.Info("Request started") // INFO: [Request started] request_id=7 tenant=acme
+//
+// - Named loggers
+//
+// "Named" returns a child logger, which adds its name to each message. The level of the named loggers
+// can be overridden per name prefix with [logger.SetNamedLevel] regardless of the levels of the loggers
+// at "Info" or below. The loggers with a higher level keep filtering the messages by their own level.
+//
+//	poolLog := logger.Named("db").Named("pool")
+//	logger.SetNamedLevel("db.pool", levels.Debug)
+//	poolLog.Debug("Connection acquired") // DEBUG: [Connection acquired] logger=db.pool
+//
+// - Context
+//
+// Functions "DebugCtx" ... "FatalCtx" log with the logger stored in the context by [logger.NewContext]
+// and add the fields returned by the functions registered with [logger.RegisterContextExtractor].
+//
+//	ctx = logger.NewContext(ctx, reqLog)
+//	logger.InfoCtx(ctx, "Request started")
+//	logger.FromContext(ctx).Info("Request done")
+//
+// - Caller location
+//
+// Use [logger.SetCallerMode] to add the location of the log call to the messages
+// as "file.go:123" ([logger.CallerFile]) or full function name ([logger.CallerFunction]).
+//
+//	logger.SetCallerMode(logger.CallerFile)
+//	logger.Info("Message") // 2024/01/14 10:00:00 main.go:12: INFO: [Message]
+//
+// - Stack traces
+//
+// Use [logger.SetStackTrace] to add stack traces to the messages in Error level or higher
+// and optionally to the messages, which log an error. The stack trace of an "*errors.Error"
+// is used when present, otherwise it is captured at the log call.
+//
+//	logger.SetStackTrace(logger.StackTraceOptions{Enabled: true, Errors: true})
+//
 // - Change log level
 //
 // To change the default log level use "SetLevel(levels.All)".
@@ -72,6 +125,16 @@
 //	logger.Debug("Test debug log message")
 //	logger.Trace("Test trace log message")
 //
+// - Parse log levels
+//
+// "levels.Parse" converts names like "warning" into log levels. The levels can be used
+// in JSON and YAML configuration and as command line flags.
+// [logger.SetLevelsFromEnv] sets the levels from the environment variables
+// "GOMULTILOG_LEVEL" (the default logger) and "GOMULTILOG_LEVEL_<KEY>" (the registered loggers).
+//
+//	level, err := levels.Parse("warning")
+//	err = logger.SetLevelsFromEnv()
+//
 // - Stop and Start logging
 //
 // No messages will be logged after calling "Stop" function.
@@ -83,6 +146,22 @@
 //	logger.DefaultLogger().Start()
 //	logger.Info("Message 2")
 //
+// - Fatal handler
+//
+// After a message in Fatal level is logged, the fatal handler is called:
+// [logger.PanicOnFatal] (default), [logger.ExitOnFatal] or a custom function.
+//
+//	logger.SetFatalHandler(logger.ExitOnFatal(1)) // flushes all loggers and exits with code 1
+//
+// - Shutdown
+//
+// "Shutdown" stops accepting messages, flushes and closes all registered loggers
+// within the context deadline and returns their errors joined together.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	defer logger.Shutdown(ctx)
+//
 // # Manage Multiple Loggers Types
 //
 // Use the following functions to register, unregister or get loggers by key. One default logger always exists and can not be unregistered, but can be stopped.
@@ -97,6 +176,44 @@
 //
 //	logger.DefaultLogger() // will return an instance of the default logger of type "ConsoleLogger"
 //
+// The package level functions log in the loggers of a default [logger.MultiLogger].
+// Use [logger.New] to create an instance with its own registry of loggers, fatal handler and shutdown.
+//
+//	l := logger.New()
+//	l.Register("file", loggers.NewFileLoggerDefault())
+//	l.Info("Message")
+//
+// # Configuration file
+//
+// Use [logger.ConfigureFromFile] to build and register the loggers described in a JSON or YAML file.
+// Nothing is registered if the configuration is not valid. See [logger.Config] for all the options.
+//
+//	level: warning
+//	loggers:
+//	  app_file:
+//	    type: file
+//	    level: debug
+//	    format: json
+//	    file:
+//	      directory: /var/log/app
+//	      rollover: daily
+//	      max_age: 168h
+//
+//	err := logger.ConfigureFromFile("log.yaml")
+//
+// Use [logger.WatchConfigFile] to poll the file and apply its changes while the process keeps logging.
+// The replaced loggers are closed after the log calls using them are finished, so no lines are lost.
+//
+//	watcher, err := logger.WatchConfigFile("log.yaml", 10*time.Second)
+//	defer watcher.Close()
+//
+// # Admin HTTP handler
+//
+// The package "logger/admin" provides an [net/http.Handler], which lists the registered loggers
+// and changes their levels and stopped states at runtime, optionally with automatic revert of a temporary level.
+//
+//	mux.Handle("/admin/loggers/", http.StripPrefix("/admin/loggers", admin.NewHandler(nil)))
+//
 // # Console logger
 //
 // - Default console log:
@@ -112,10 +229,29 @@
 //	c := loggers.NewConsoleLogger(levels.Debug, "***debug:'%s'")
 //	err := logger.RegisterLogger("debug_log_key", c)
 //
+// - Pretty console log:
+//
+// "NewPrettyConsoleLogger" prints human-friendly lines with short time, aligned columns and colors per log level.
+// With "ColorAuto" the colors are used only if the standard output is a terminal and "NO_COLOR" is not set.
+//
+//	logger.RegisterLogger("pretty", loggers.NewPrettyConsoleLogger(levels.Debug, loggers.ColorAuto))
+//
+// - Template format:
+//
+// Use "NewConsoleLoggerTemplate" or "NewFileLoggerTemplate" to format the whole line with named placeholders
+// {time} or {time:layout}, {level}, {caller}, {message}, {fields} and {stack}. An error is returned for invalid templates.
+//
+//	c, err := loggers.NewConsoleLoggerTemplate(levels.Info, "{time:15:04:05.000} {level} {caller} {message} {fields}")
+//
 // # File logger
 //
 // "FileLogger" can be added as an additional logger to prints the messages to files.
 //
+// By default the messages of all goroutines are written into a single file {prefix}_{pid}{ext}.
+// Set "PerGoroutine" in [loggers.FileOptions] to write the messages of each goroutine
+// into a separate file {prefix}_{pid}_{goid}{ext}, or "TagGoroutine" to add
+// the goroutine id to each line of the single file.
+//
 // - Use "NewFileLoggerDefault" to initialize the file logger with the default settings.
 //
 //   - Default [LogLevel] is levels.Info.
@@ -147,6 +283,81 @@
 //	f := loggers.NewFileLogger(level, format, fileOptions)
 //	err := logger.RegisterLogger("txt_file_key", f)
 //
+// - Rotation of log files
+//
+// The rotation options in [loggers.FileOptions] limit the size of the log files.
+// A rotated file is renamed to {name}-{time}{ext} and optionally compressed with gzip.
+//
+//	fileOptions := loggers.FileOptions{
+//	    FilePrefix:    "mLog",
+//	    FileExtension: ".log",
+//	    MaxSize:       10 * 1024 * 1024,
+//	    Rollover:      loggers.RolloverDaily,
+//	    MaxBackups:    7,
+//	    MaxAge:        30 * 24 * time.Hour,
+//	    Compress:      true,
+//	}
+//
+// - Buffered writes
+//
+// The log files are kept open between the writes. Set "BufferSize" in [loggers.FileOptions]
+// to buffer the writes. The buffered messages are written when the buffer is full,
+// every "FlushInterval" and when "Flush", "Stop" or "Close" is called.
+//
+//	f := loggers.NewFileLogger(levels.Info, "", loggers.FileOptions{BufferSize: 64 * 1024})
+//	defer f.Close()
+//
+// # JSON logger
+//
+// "JSONLogger" prints every message as a single line JSON object with keys
+// "time", "level", "msg", "error" (for error objects) and the structured fields.
+// It prints to any [io.Writer] and uses [os.Stdout] when the writer is nil.
+//
+//	f, err := os.OpenFile("app.json", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+//	j := loggers.NewJSONLogger(levels.Info, f)
+//	err = logger.RegisterLogger("json_logger_key", j)
+//
+// # Logfmt logger
+//
+// "LogfmtLogger" prints every message as a logfmt line with keys "ts", "level", "msg",
+// "caller", "error", the structured fields and "stack". Values are quoted and escaped when needed.
+//
+//	l := loggers.NewLogfmtLogger(levels.Info, os.Stdout)
+//	// ts=2024-01-14T10:00:00.123+02:00 level=info msg="Request done" user="John Smith"
+//
+// # Formatters and writer logger
+//
+// A formatter implements [loggers.FormatterInterface] and formats a [loggers.Entry] as a single line.
+// "TextFormatter", "JSONFormatter", "LogfmtFormatter" and "Template" can be combined with any [io.Writer]
+// using "NewWriterLogger" or with log files using "NewFileLoggerFormatter".
+//
+//	w := loggers.NewWriterLogger(levels.Info, conn, loggers.JSONFormatter{})
+//	f := loggers.NewFileLoggerFormatter(levels.Info, loggers.JSONFormatter{}, options)
+//
+// # Async logger
+//
+// "AsyncLogger" wraps any logger and prints its messages in a background goroutine.
+// The messages wait in a bounded queue and [loggers.OverflowPolicy] defines
+// what happens when the queue is full. "Stop" waits until the queued messages are printed.
+//
+//	a := loggers.NewAsyncLogger(loggers.NewFileLoggerDefault(), loggers.AsyncOptions{
+//	    QueueSize: 4096,
+//	    Overflow:  loggers.OverflowDropOldest,
+//	})
+//	err := logger.RegisterLogger("async_file_key", a)
+//
+// # Package "log/slog"
+//
+// [logger.NewSlogHandler] returns a [log/slog.Handler], which logs the records
+// in all registered loggers. [loggers.NewSlogLogger] registers a logger,
+// which passes the messages to any [log/slog.Handler].
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler()))
+//	slog.Info("Request done", "user", 42)
+//
+//	h := slog.NewJSONHandler(os.Stderr, nil)
+//	err := logger.RegisterLogger("slog_key", loggers.NewSlogLogger(levels.Info, h))
+//
 // # Custom loggers
 //
 // Custom loggers implementations can be easily added by implementing
@@ -0,0 +1,143 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func registerFatalRecorder(t *testing.T) (*recordingLogger, func()) {
+	logger.DefaultLogger().Stop()
+	r := newRecordingLogger()
+	key := "fatal_recording_key"
+	err := logger.RegisterLogger(key, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, func() {
+		logger.UnregisterLogger(key)
+		logger.DefaultLogger().Start()
+	}
+}
+
+func TestFatalPanics(t *testing.T) {
+	r, cleanup := registerFatalRecorder(t)
+	defer cleanup()
+
+	assert.PanicsWithValue(t, "Fatal message", func() {
+		logger.Fatal("Fatal message")
+	})
+	assert.PanicsWithValue(t, "Fatal message 2", func() {
+		logger.FatalF("Fatal message %d", 2)
+	})
+	assert.PanicsWithValue(t, "Fatal message 3", func() {
+		logger.FatalKV("Fatal message 3", "user", 42)
+	})
+	assert.Equal(t, []string{"Fatal message", "Fatal message 2", "Fatal message 3 user=42"}, r.Messages())
+}
+
+func TestFatalPanicFlushes(t *testing.T) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	options := newBufferedOptions(t)
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+	if err := l.Register("buffered_file", fileLogger); err != nil {
+		t.Fatal(err)
+	}
+	asyncOptions := newBufferedOptions(t)
+	asyncLogger := loggers.NewAsyncLogger(loggers.NewFileLogger(levels.Info, "", asyncOptions), loggers.AsyncOptions{})
+	defer asyncLogger.Close()
+	if err := l.Register("async_file", asyncLogger); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.PanicsWithValue(t, "Fatal message", func() {
+		l.Fatal("Fatal message")
+	})
+	// The buffered messages are written before the panic.
+	assert.Contains(t, readDirContent(t, options.Directory), "Fatal message")
+	assert.Contains(t, readDirContent(t, asyncOptions.Directory), "Fatal message")
+}
+
+func TestFatalHook(t *testing.T) {
+	r, cleanup := registerFatalRecorder(t)
+	defer cleanup()
+
+	var fatalArgs []any
+	logger.SetFatalHandler(func(arg any) {
+		fatalArgs = append(fatalArgs, arg)
+	})
+	defer logger.SetFatalHandler(nil)
+
+	assert.NotPanics(t, func() {
+		logger.Fatal(person{Name: "Michael"})
+		logger.FatalF("Person: %v", person{Name: "Michael"})
+		logger.FatalKV("Fatal message", "user", 42)
+	})
+	assert.Equal(t, []any{person{Name: "Michael"}, "Person: {Michael}", "Fatal message"}, fatalArgs)
+	assert.Len(t, r.Messages(), 3)
+
+	logger.SetFatalHandler(nil)
+	assert.Panics(t, func() {
+		logger.FatalF("Fatal message")
+	})
+}
+
+func TestFatalExit(t *testing.T) {
+	for _, scenario := range []string{"Fatal", "FatalF", "FatalKV"} {
+		dir := t.TempDir()
+		output, code := runSubprocess(t, "TestFatalExitSubprocess", scenario, "LOG_DIR="+dir)
+		assert.Equal(t, 3, code, output)
+		assert.NotContains(t, output, "Message after fatal")
+
+		// The buffered messages are written before the exit.
+		content := readDirContent(t, dir)
+		assert.Contains(t, content, "Info message")
+		assert.Contains(t, content, "Fatal message")
+	}
+}
+
+func TestFatalExitSubprocess(t *testing.T) {
+	scenario := os.Getenv(subprocessEnv)
+	if len(scenario) == 0 {
+		t.Skip("runs only in a subprocess started by TestFatalExit")
+	}
+	options := newBufferedOptions(t)
+	options.Directory = os.Getenv("LOG_DIR")
+	err := logger.RegisterLogger("buffered_file", loggers.NewFileLogger(levels.Info, "", options))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.SetFatalHandler(logger.ExitOnFatal(3))
+
+	logger.Info("Info message")
+	switch scenario {
+	case "Fatal":
+		logger.Fatal("Fatal message")
+	case "FatalF":
+		logger.FatalF("Fatal message %d", 1)
+	case "FatalKV":
+		logger.FatalKV("Fatal message", "user", 42)
+	}
+	logger.Info("Message after fatal")
+}
@@ -0,0 +1,126 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// recordingLogger implements only [loggers.LoggerInterface]
+// and keeps the logged messages in memory.
+type recordingLogger struct {
+	loggers.LoggerType
+	mu       sync.Mutex
+	messages []string
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{LoggerType: loggers.LoggerType{Level: levels.All}}
+}
+
+func (logger *recordingLogger) Log(level levels.LogLevel, arg any) {
+	logger.LogF(level, "%v", arg)
+}
+
+func (logger *recordingLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
+	if logger.IsLogAllowed(level) {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		logger.messages = append(logger.messages, fmt.Sprintf(format, args...))
+	}
+}
+
+func (logger *recordingLogger) Messages() []string {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	return append([]string(nil), logger.messages...)
+}
+
+func TestFieldsFromKeysAndValues(t *testing.T) {
+	fields := loggers.Fields("user", 42, loggers.F("tenant", "acme"), 7, "x", "missing")
+	assert.Equal(t, []loggers.Field{
+		{Key: "user", Value: 42},
+		{Key: "tenant", Value: "acme"},
+		{Key: "7", Value: "x"},
+		{Key: "missing", Value: nil},
+	}, fields)
+}
+
+func TestFormatFields(t *testing.T) {
+	content := loggers.FormatFields([]loggers.Field{
+		loggers.F("user", 42),
+		loggers.F("latency", 1500*time.Millisecond),
+		loggers.F("name", "John Smith"),
+		loggers.F("quote", `say "hi"`),
+		loggers.F("empty", ""),
+	})
+	assert.Equal(t, `user=42 latency=1.5s name="John Smith" quote="say \"hi\"" empty=""`, content)
+}
+
+func TestConsoleLogKV(t *testing.T) {
+	content := readConsole(func() {
+		logger.InfoKV("Request done", "user", 42, "latency", 1500*time.Millisecond)
+		logger.DebugKV("Skipped message", "user", 42)
+	})
+
+	assert.Contains(t, content, "INFO: [Request done] user=42 latency=1.5s")
+	assert.NotContains(t, content, "Skipped message")
+}
+
+func TestFileLogKV(t *testing.T) {
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+	}
+	key := "kv_file_key"
+	err := logger.RegisterLogger(key, loggers.NewFileLogger(levels.Info, "file:'%s'", fileOptions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	logger.DefaultLogger().Stop()
+	logger.WarningKV("Disk is almost full", loggers.F("free", "1 GB"), "path", "/var")
+	logger.DefaultLogger().Start()
+
+	content := removeLogFiles(t, fileOptions)
+	assert.Contains(t, content, `file:'Disk is almost full' free="1 GB" path=/var`)
+}
+
+func TestLogKVWithoutFieldsSupport(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	r := newRecordingLogger()
+	key := "recording_key"
+	err := logger.RegisterLogger(key, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	logger.ErrorKV("Payment failed", "order", 1001)
+	assert.Equal(t, []string{"Payment failed order=1001"}, r.Messages())
+}
@@ -0,0 +1,194 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func newBufferedOptions(t testing.TB) loggers.FileOptions {
+	return loggers.FileOptions{
+		Directory:     t.TempDir(),
+		FilePrefix:    "bLog",
+		FileExtension: ".log",
+		BufferSize:    4096,
+		FlushInterval: time.Hour,
+	}
+}
+
+// Returns the joined content of all files in the directory.
+func readDirContent(t *testing.T, dir string) string {
+	var content strings.Builder
+	for _, name := range listFiles(t, dir) {
+		content.WriteString(readFileContent(t, filepath.Join(dir, name)))
+	}
+	return content.String()
+}
+
+func TestFileLoggerFlush(t *testing.T) {
+	options := newBufferedOptions(t)
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+
+	fileLogger.Log(levels.Info, "Buffered message")
+	assert.NotContains(t, readDirContent(t, options.Directory), "Buffered message")
+
+	assert.NoError(t, fileLogger.Flush())
+	assert.Contains(t, readDirContent(t, options.Directory), "Buffered message")
+}
+
+func TestFileLoggerFlushOnStop(t *testing.T) {
+	options := newBufferedOptions(t)
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+
+	fileLogger.Log(levels.Info, "Buffered message")
+	fileLogger.Stop()
+	assert.Contains(t, readDirContent(t, options.Directory), "Buffered message")
+
+	fileLogger.Log(levels.Info, "Stopped message")
+	fileLogger.Start()
+	fileLogger.Log(levels.Info, "Started message")
+	assert.NoError(t, fileLogger.Close())
+
+	content := readDirContent(t, options.Directory)
+	assert.NotContains(t, content, "Stopped message")
+	assert.Contains(t, content, "Started message")
+}
+
+func TestFileLoggerFlushInterval(t *testing.T) {
+	options := newBufferedOptions(t)
+	options.FlushInterval = 10 * time.Millisecond
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+
+	fileLogger.Log(levels.Info, "Buffered message")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(readDirContent(t, options.Directory), "Buffered message")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestFileLoggerFlushOnBufferSize(t *testing.T) {
+	options := newBufferedOptions(t)
+	options.BufferSize = 100
+	fileLogger := loggers.NewFileLogger(levels.Info, "%s", options)
+	defer fileLogger.Close()
+
+	for i := 0; i < 5; i++ {
+		fileLogger.Log(levels.Info, strings.Repeat("x", 29))
+	}
+	// Each line is 50 bytes and the buffer is written each time it is full.
+	content := readDirContent(t, options.Directory)
+	assert.Len(t, content, 200)
+}
+
+func TestFileLoggerCloseAndReopen(t *testing.T) {
+	options := newBufferedOptions(t)
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+
+	fileLogger.Log(levels.Info, "Message 1")
+	assert.NoError(t, fileLogger.Close())
+	assert.Contains(t, readDirContent(t, options.Directory), "Message 1")
+
+	fileLogger.Log(levels.Info, "Message 2")
+	assert.NoError(t, fileLogger.Close())
+	content := readDirContent(t, options.Directory)
+	assert.Contains(t, content, "Message 1")
+	assert.Contains(t, content, "Message 2")
+}
+
+func TestFileLoggerMaxOpenFiles(t *testing.T) {
+	options := newBufferedOptions(t)
+	options.PerGoroutine = true
+	options.MaxOpenFiles = 2
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				fileLogger.LogF(levels.Info, "goroutine %d message %d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.NoError(t, fileLogger.Close())
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 10)
+	for _, name := range names {
+		content := readFileContent(t, filepath.Join(options.Directory, name))
+		assert.Equal(t, 10, strings.Count(content, "\n"))
+	}
+}
+
+func benchmarkFileLogger(b *testing.B, options loggers.FileOptions) {
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fileLogger.LogF(levels.Info, "Benchmark message %d", i)
+	}
+}
+
+// Opens and closes the file for every message
+// as the FileLogger did before keeping the files open.
+func BenchmarkFileOpenPerWrite(b *testing.B) {
+	fName := filepath.Join(b.TempDir(), fmt.Sprintf("oLog_%d.log", os.Getpid()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fLog, err := os.OpenFile(fName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+		if err != nil {
+			b.Fatal(err)
+		}
+		log.New(fLog, "", log.LstdFlags).Printf("Benchmark message %d", i)
+		fLog.Close()
+	}
+}
+
+func BenchmarkFileLoggerUnbuffered(b *testing.B) {
+	options := newBufferedOptions(b)
+	options.BufferSize = 0
+	benchmarkFileLogger(b, options)
+}
+
+func BenchmarkFileLoggerBuffered(b *testing.B) {
+	benchmarkFileLogger(b, newBufferedOptions(b))
+}
+
+func BenchmarkFileLoggerBufferedParallel(b *testing.B) {
+	fileLogger := loggers.NewFileLogger(levels.Info, "", newBufferedOptions(b))
+	defer fileLogger.Close()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			fileLogger.Log(levels.Info, "Benchmark message")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+const fileModeGoroutines = 20
+const fileModeMessages = 50
+
+// Logs from many goroutines at the same time.
+func logFromGoroutines(fileLogger *loggers.FileLogger) {
+	var wg sync.WaitGroup
+	for g := 0; g < fileModeGoroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < fileModeMessages; i++ {
+				fileLogger.LogF(levels.Info, "goroutine %d message %d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestSharedFileMode(t *testing.T) {
+	options := loggers.FileOptions{
+		Directory:     t.TempDir(),
+		FilePrefix:    "sLog",
+		FileExtension: ".log",
+	}
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+	logFromGoroutines(fileLogger)
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 1)
+	content := readFileContent(t, filepath.Join(options.Directory, names[0]))
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	assert.Len(t, lines, fileModeGoroutines*fileModeMessages)
+	linePattern := regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} goroutine \d+ message \d+$`)
+	for _, line := range lines {
+		assert.Regexp(t, linePattern, line)
+	}
+}
+
+func TestPerGoroutineFileMode(t *testing.T) {
+	options := loggers.FileOptions{
+		Directory:     t.TempDir(),
+		FilePrefix:    "gLog",
+		FileExtension: ".log",
+		PerGoroutine:  true,
+	}
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+	logFromGoroutines(fileLogger)
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, fileModeGoroutines)
+	for _, name := range names {
+		content := readFileContent(t, filepath.Join(options.Directory, name))
+		assert.Equal(t, fileModeMessages, strings.Count(content, "\n"))
+	}
+}
+
+func TestTagGoroutineFileMode(t *testing.T) {
+	options := loggers.FileOptions{
+		Directory:     t.TempDir(),
+		FilePrefix:    "tLog",
+		FileExtension: ".log",
+		TagGoroutine:  true,
+	}
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+	logFromGoroutines(fileLogger)
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 1)
+	content := readFileContent(t, filepath.Join(options.Directory, names[0]))
+	tagPattern := regexp.MustCompile(`(?m)^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} \[(\d+)\] goroutine (\d+) message \d+$`)
+	matches := tagPattern.FindAllStringSubmatch(content, -1)
+	assert.Len(t, matches, fileModeGoroutines*fileModeMessages)
+
+	// All the messages of one goroutine have the same tag.
+	tags := map[string]string{}
+	for _, match := range matches {
+		if tag, ok := tags[match[2]]; ok {
+			assert.Equal(t, tag, match[1], fmt.Sprintf("goroutine %s", match[2]))
+		}
+		tags[match[2]] = match[1]
+	}
+	assert.Len(t, tags, fileModeGoroutines)
+}
+
+func TestTagGoroutineJSONFileMode(t *testing.T) {
+	options := loggers.FileOptions{
+		Directory:     t.TempDir(),
+		FilePrefix:    "jLog",
+		FileExtension: ".json",
+		TagGoroutine:  true,
+	}
+	fileLogger := loggers.NewFileLoggerFormatter(levels.Info, loggers.JSONFormatter{}, options)
+	defer fileLogger.Close()
+	logFromGoroutines(fileLogger)
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 1)
+	entries := decodeJSONLines(t, readFileContent(t, filepath.Join(options.Directory, names[0])))
+	assert.Len(t, entries, fileModeGoroutines*fileModeMessages)
+
+	// All the messages of one goroutine have the same id.
+	pattern := regexp.MustCompile(`^goroutine (\d+) message \d+$`)
+	tags := map[string]any{}
+	for _, entry := range entries {
+		match := pattern.FindStringSubmatch(fmt.Sprint(entry["msg"]))
+		if !assert.NotNil(t, match) || !assert.Contains(t, entry, "goid") {
+			continue
+		}
+		if tag, ok := tags[match[1]]; ok {
+			assert.Equal(t, tag, entry["goid"], fmt.Sprintf("goroutine %s", match[1]))
+		}
+		tags[match[1]] = entry["goid"]
+	}
+	assert.Len(t, tags, fileModeGoroutines)
+}
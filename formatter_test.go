@@ -0,0 +1,115 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// xmlFormatter is a custom formatter, which formats the entries as XML elements.
+type xmlFormatter struct{}
+
+func (xmlFormatter) Format(entry loggers.Entry) []byte {
+	return []byte(fmt.Sprintf("<entry level=%q>%s</entry>\n", entry.Level, entry.Message))
+}
+
+func newFormatterEntry() loggers.Entry {
+	return loggers.Entry{
+		Time:    time.Date(2024, 1, 14, 10, 5, 30, 0, time.UTC),
+		Level:   levels.Error,
+		Message: "Payment failed",
+		Caller:  "main.go:12",
+		Stack:   "main.main\n\t/app/main.go:12",
+		Fields:  []loggers.Field{loggers.F("order", 1001)},
+		Error:   errors.Errorf("card declined").Err,
+	}
+}
+
+func TestTextFormatter(t *testing.T) {
+	assert.Equal(t,
+		"2024/01/14 10:05:30 main.go:12: ERROR: [Payment failed] order=1001\n\tmain.main\n\t\t/app/main.go:12\n",
+		string(loggers.TextFormatter{}.Format(newFormatterEntry())))
+
+	entry := loggers.Entry{Time: time.Date(2024, 1, 14, 10, 5, 30, 0, time.UTC), Level: levels.Info, Message: "Message"}
+	assert.Equal(t, "2024/01/14 10:05:30 INFO: [Message]\n", string(loggers.TextFormatter{}.Format(entry)))
+}
+
+func TestJSONFormatter(t *testing.T) {
+	assert.Equal(t,
+		`{"time":"2024-01-14T10:05:30Z","level":"Error","msg":"Payment failed","caller":"main.go:12","error":"card declined","order":1001,"stack":"main.main\n\t/app/main.go:12"}`+"\n",
+		string(loggers.JSONFormatter{}.Format(newFormatterEntry())))
+}
+
+func TestWriterLoggerFormatters(t *testing.T) {
+	template, err := loggers.ParseTemplate("{level} {message} {fields}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		formatter loggers.FormatterInterface
+		expected  string
+	}{
+		{nil, `^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d WARNING: \[Message 1\] user=42\n\d{4}/\d\d/\d\d \d\d:\d\d:\d\d ERROR: \[Message 2\]\n$`},
+		{loggers.JSONFormatter{}, `^\{"time":"[^"]+","level":"Warning","msg":"Message 1","user":42\}\n\{"time":"[^"]+","level":"Error","msg":"Message 2","error":"Message 2"\}\n$`},
+		{template, `^WARNING Message 1 user=42\nERROR Message 2\n$`},
+		{xmlFormatter{}, `^<entry level="Warning">Message 1</entry>\n<entry level="Error">Message 2</entry>\n$`},
+	} {
+		var buf bytes.Buffer
+		writerLogger := loggers.NewWriterLogger(levels.Warning, &buf, test.formatter)
+		writerLogger.LogKV(levels.Warning, "Message 1", loggers.F("user", 42))
+		writerLogger.Log(levels.Error, errors.Errorf("Message 2").Err)
+		writerLogger.LogF(levels.Info, "Skipped message")
+		assert.Regexp(t, regexp.MustCompile(test.expected), buf.String())
+	}
+}
+
+func TestWriterLoggerConsole(t *testing.T) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	l.Register("xml", loggers.NewWriterLogger(levels.Info, nil, xmlFormatter{}))
+	content := readConsole(func() {
+		l.Info("Message")
+	})
+	assert.Equal(t, "<entry level=\"Info\">Message</entry>\n", content)
+}
+
+func TestFileLoggerFormatter(t *testing.T) {
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+	}
+	fileLogger := loggers.NewFileLoggerFormatter(levels.Info, loggers.JSONFormatter{}, fileOptions)
+	fileLogger.LogKV(levels.Info, "Message 1", loggers.F("user", 42))
+	fileLogger.LogF(levels.Warning, "Message %d", 2)
+	fileLogger.Close()
+
+	entries := decodeJSONLines(t, removeLogFiles(t, fileOptions))
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Message 1", entries[0]["msg"])
+	assert.Equal(t, float64(42), entries[0]["user"])
+	assert.Equal(t, "Warning", entries[1]["level"])
+}
@@ -6,10 +6,10 @@ require (
 	github.com/go-errors/errors v1.5.1
 	github.com/stretchr/testify v1.9.0
 	github.com/timandy/routine v1.1.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
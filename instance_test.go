@@ -0,0 +1,122 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// Returns a new [logger.MultiLogger] with a stopped console logger
+// and a registered recording logger.
+func newRecordingMultiLogger(t *testing.T) (*logger.MultiLogger, *recordingLogger) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	r := newRecordingLogger()
+	if err := l.Register("recording", r); err != nil {
+		t.Fatal(err)
+	}
+	return l, r
+}
+
+func TestMultiLoggerIsolated(t *testing.T) {
+	first, firstRecorder := newRecordingMultiLogger(t)
+	second, secondRecorder := newRecordingMultiLogger(t)
+	assert.NotSame(t, first.DefaultLogger(), second.DefaultLogger())
+	assert.NotSame(t, logger.DefaultLogger(), first.DefaultLogger())
+	assert.Nil(t, logger.GetLogger("recording"))
+
+	first.Info("First message")
+	second.InfoF("Second message %d", 2)
+	second.WarningKV("Third message", "user", 42)
+	logger.Info("Package message")
+
+	assert.Equal(t, []string{"First message"}, firstRecorder.Messages())
+	assert.Equal(t, []string{"Second message 2", "Third message user=42"}, secondRecorder.Messages())
+
+	assert.NoError(t, first.Unregister("recording"))
+	assert.Error(t, first.Unregister("recording"))
+	assert.Error(t, first.Register("", firstRecorder))
+	assert.Nil(t, first.Get("recording"))
+	assert.Same(t, secondRecorder, second.Get("recording"))
+}
+
+func TestMultiLoggerParallelLevels(t *testing.T) {
+	for _, level := range []levels.LogLevel{levels.Debug, levels.Info, levels.Error} {
+		level := level
+		t.Run(fmt.Sprintf("level %d", level), func(t *testing.T) {
+			t.Parallel()
+			l, r := newRecordingMultiLogger(t)
+			r.SetLevel(level)
+			for i := 0; i < 100; i++ {
+				l.Debug("Debug message")
+				l.Info("Info message")
+				l.Error("Error message")
+			}
+			expected := map[levels.LogLevel]int{levels.Debug: 300, levels.Info: 200, levels.Error: 100}
+			assert.Len(t, r.Messages(), expected[level])
+		})
+	}
+}
+
+func TestMultiLoggerFatalHandler(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	var fatalArgs []any
+	l.SetFatalHandler(func(arg any) {
+		fatalArgs = append(fatalArgs, arg)
+	})
+
+	assert.NotPanics(t, func() {
+		l.Fatal("Fatal message")
+		l.FatalF("Fatal message %d", 2)
+		l.FatalKV("Fatal message 3", "user", 42)
+	})
+	assert.Equal(t, []any{"Fatal message", "Fatal message 2", "Fatal message 3"}, fatalArgs)
+	assert.Equal(t, []string{"Fatal message", "Fatal message 2", "Fatal message 3 user=42"}, r.Messages())
+
+	// Other instances keep the default handler.
+	other, _ := newRecordingMultiLogger(t)
+	assert.PanicsWithValue(t, "Other fatal message", func() {
+		other.Fatal("Other fatal message")
+	})
+}
+
+func TestMultiLoggerShutdown(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	closing := &closeLogger{}
+	if err := l.Register("closing", closing); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("Message 1")
+	assert.NoError(t, l.Shutdown(context.Background()))
+	l.Info("Message after shutdown")
+
+	assert.Equal(t, []string{"Message 1"}, r.Messages())
+	assert.Equal(t, []string{"Message 1"}, closing.Messages())
+	assert.NotNil(t, logger.DefaultLogger())
+}
+
+func TestMultiLoggerSlogHandler(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	slog.New(l.SlogHandler()).Info("Slog message", "user", 42)
+	assert.Equal(t, []string{"Slog message user=42"}, r.Messages())
+}
@@ -0,0 +1,96 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func TestJSONLogger(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	var buf bytes.Buffer
+	key := "json_key"
+	err := logger.RegisterLogger(key, loggers.NewJSONLogger(levels.Info, &buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	logger.Debug("Skipped message")
+	logger.Info(person{Name: "Michael"})
+	logger.WarningF("Person: %v", person{Name: "Michael"})
+	logger.Error(errors.Errorf("Test error object").Err)
+	logger.InfoKV("Request done", "user", 42, "latency", 1500*time.Millisecond, "ok", true)
+
+	entries := decodeJSONLines(t, buf.String())
+	assert.Len(t, entries, 4)
+
+	assert.Equal(t, "Info", entries[0]["level"])
+	assert.Equal(t, "{Michael}", entries[0]["msg"])
+	_, err = time.Parse(time.RFC3339Nano, entries[0]["time"].(string))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Warning", entries[1]["level"])
+	assert.Equal(t, "Person: {Michael}", entries[1]["msg"])
+
+	assert.Equal(t, "Error", entries[2]["level"])
+	assert.Equal(t, "Test error object", entries[2]["msg"])
+	assert.Equal(t, "Test error object", entries[2]["error"])
+
+	assert.Equal(t, "Request done", entries[3]["msg"])
+	assert.Equal(t, float64(42), entries[3]["user"])
+	assert.Equal(t, float64(1500*time.Millisecond), entries[3]["latency"])
+	assert.Equal(t, true, entries[3]["ok"])
+}
+
+func TestJSONLoggerEscapesValues(t *testing.T) {
+	var buf bytes.Buffer
+	jsonLogger := loggers.NewJSONLogger(levels.All, &buf)
+	jsonLogger.LogKV(levels.Debug, "line 1\nline \"2\"", loggers.F("channel", make(chan int)))
+
+	entries := decodeJSONLines(t, buf.String())
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Debug", entries[0]["level"])
+	assert.Equal(t, "line 1\nline \"2\"", entries[0]["msg"])
+	assert.IsType(t, "", entries[0]["channel"])
+}
+
+func decodeJSONLines(t *testing.T, content string) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
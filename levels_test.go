@@ -0,0 +1,137 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func TestParseLevel(t *testing.T) {
+	for text, expected := range map[string]levels.LogLevel{
+		"all":     levels.All,
+		"Debug":   levels.Debug,
+		"TRACE":   levels.Trace,
+		" info ":  levels.Info,
+		"warning": levels.Warning,
+		"warn":    levels.Warning,
+		"error":   levels.Error,
+		"Fatal":   levels.Fatal,
+		"4":       levels.Warning,
+		"0":       levels.All,
+	} {
+		level, err := levels.Parse(text)
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, level, text)
+	}
+	for _, text := range []string{"", "verbose", "7", "-1", "info!"} {
+		_, err := levels.Parse(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestLevelText(t *testing.T) {
+	for _, level := range []levels.LogLevel{levels.All, levels.Debug, levels.Trace, levels.Info, levels.Warning, levels.Error, levels.Fatal} {
+		text, err := level.MarshalText()
+		assert.NoError(t, err)
+		var parsed levels.LogLevel
+		assert.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, level, parsed)
+	}
+	_, err := levels.LogLevel(10).MarshalText()
+	assert.Error(t, err)
+}
+
+func TestLevelJSON(t *testing.T) {
+	type config struct {
+		Level levels.LogLevel `json:"level"`
+	}
+	data, err := json.Marshal(config{Level: levels.Warning})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"level":"warning"}`, string(data))
+
+	var c config
+	assert.NoError(t, json.Unmarshal([]byte(`{"level":"ERROR"}`), &c))
+	assert.Equal(t, levels.Error, c.Level)
+	assert.NoError(t, json.Unmarshal([]byte(`{"level":2}`), &c))
+	assert.Equal(t, levels.Trace, c.Level)
+	assert.Error(t, json.Unmarshal([]byte(`{"level":"verbose"}`), &c))
+	assert.Error(t, json.Unmarshal([]byte(`{"level":9}`), &c))
+	assert.Error(t, json.Unmarshal([]byte(`{"level":true}`), &c))
+}
+
+func TestLevelFlag(t *testing.T) {
+	level := levels.Info
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&level, "log-level", "the log level")
+	assert.NoError(t, flags.Parse([]string{"-log-level", "debug"}))
+	assert.Equal(t, levels.Debug, level)
+	assert.Equal(t, "Debug", flags.Lookup("log-level").Value.String())
+
+	flags.SetOutput(io.Discard)
+	assert.Error(t, flags.Parse([]string{"-log-level", "verbose"}))
+}
+
+func TestLevelEnvName(t *testing.T) {
+	assert.Equal(t, "GOMULTILOG_LEVEL", logger.LevelEnvName(""))
+	assert.Equal(t, "GOMULTILOG_LEVEL_JSON_FILE", logger.LevelEnvName("json-file"))
+	assert.Equal(t, "GOMULTILOG_LEVEL_DB_POOL_2", logger.LevelEnvName("db.pool 2"))
+}
+
+func TestSetLevelsFromEnv(t *testing.T) {
+	l := logger.New()
+	jsonLogger := loggers.NewJSONLogger(levels.Info, nil)
+	fileLogger := loggers.NewFileLoggerDefault()
+	other := newRecordingLogger()
+	l.Register("json-file", jsonLogger)
+	l.Register("file", fileLogger)
+	l.Register("other", other)
+
+	t.Setenv("GOMULTILOG_LEVEL", "debug")
+	t.Setenv("GOMULTILOG_LEVEL_JSON_FILE", "WARN")
+	t.Setenv("GOMULTILOG_LEVEL_FILE", "verbose")
+	err := l.SetLevelsFromEnv()
+
+	assert.Equal(t, levels.Debug, l.DefaultLogger().GetLevel())
+	assert.Equal(t, levels.Warning, jsonLogger.GetLevel())
+	assert.Equal(t, levels.Info, fileLogger.GetLevel())
+	assert.Equal(t, levels.All, other.GetLevel())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "GOMULTILOG_LEVEL_FILE")
+		assert.Contains(t, err.Error(), `"verbose"`)
+	}
+}
+
+func TestLoggerTypeLevel(t *testing.T) {
+	c := loggers.NewConsoleLogger(levels.Info, "")
+	c.SetLevel(levels.Error)
+	assert.Equal(t, levels.Error, c.GetLevel())
+	assert.Equal(t, levels.Error, c.Level)
+	assert.False(t, c.IsLogAllowed(levels.Warning))
+
+	c.Stop()
+	assert.True(t, c.IsStopped())
+	assert.False(t, c.IsLogAllowed(levels.Fatal))
+	c.Start()
+	assert.True(t, c.IsLogAllowed(levels.Fatal))
+}
@@ -0,0 +1,149 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// Parses a single logfmt line into key/value pairs.
+// Quoted values are unquoted as Go strings.
+func parseLogfmt(t *testing.T, line string) []loggers.Field {
+	var pairs []loggers.Field
+	for len(line) > 0 {
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok || len(key) == 0 || strings.ContainsAny(key, " \"") {
+			t.Fatalf("invalid key in logfmt line %q", line)
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := 1
+			for ; end < len(rest) && rest[end] != '"'; end++ {
+				if rest[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(rest) {
+				t.Fatalf("not closed quote in logfmt line %q", line)
+			}
+			var err error
+			if value, err = strconv.Unquote(rest[:end+1]); err != nil {
+				t.Fatalf("invalid quoted value in logfmt line %q: %v", line, err)
+			}
+			rest = rest[end+1:]
+		} else {
+			value, rest, _ = strings.Cut(rest, " ")
+			rest = " " + rest
+		}
+		if len(rest) > 0 && rest[0] != ' ' {
+			t.Fatalf("missing space after value in logfmt line %q", line)
+		}
+		pairs = append(pairs, loggers.F(key, value))
+		line = strings.TrimPrefix(rest, " ")
+	}
+	return pairs
+}
+
+func TestLogfmtFormatter(t *testing.T) {
+	entry := loggers.Entry{
+		Time:    time.Date(2024, 1, 14, 10, 5, 30, 123000000, time.UTC),
+		Level:   levels.Warning,
+		Message: "Disk is almost full",
+		Caller:  "main.go:12",
+		Fields:  []loggers.Field{loggers.F("free", "1 GB"), loggers.F("path", "/var"), loggers.F("bad key=", 1), loggers.F("", 2)},
+		Error:   errors.Errorf("no space").Err,
+	}
+	assert.Equal(t,
+		`ts=2024-01-14T10:05:30.123Z level=warning msg="Disk is almost full" caller=main.go:12 error="no space" free="1 GB" path=/var bad_key_=1 _=2`+"\n",
+		string(loggers.LogfmtFormatter{}.Format(entry)))
+}
+
+func TestLogfmtRoundTrip(t *testing.T) {
+	values := []string{
+		"plain",
+		"with space",
+		`quote "x"`,
+		"new\nline",
+		"tab\there",
+		"a=b",
+		"",
+		`back\slash`,
+		`\"escaped\"`,
+		"unicode ✓",
+		"control \x00\x1b",
+		"trailing ",
+	}
+	var buf bytes.Buffer
+	logfmtLogger := loggers.NewLogfmtLogger(levels.All, &buf)
+	for _, value := range values {
+		logfmtLogger.LogKV(levels.Debug, value, loggers.F("value", value), loggers.F("n", 1))
+	}
+	logfmtLogger.Log(levels.Error, errors.Errorf("line 1\nline 2").Err)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, len(values)+1)
+	for i, value := range values {
+		pairs := parseLogfmt(t, lines[i])
+		if assert.Len(t, pairs, 5, lines[i]) {
+			_, err := time.Parse(time.RFC3339Nano, pairs[0].Value.(string))
+			assert.NoError(t, err)
+			assert.Equal(t, []loggers.Field{
+				loggers.F("ts", pairs[0].Value),
+				loggers.F("level", "debug"),
+				loggers.F("msg", value),
+				loggers.F("value", value),
+				loggers.F("n", "1"),
+			}, pairs)
+		}
+	}
+	pairs := parseLogfmt(t, lines[len(values)])
+	assert.Equal(t, []loggers.Field{loggers.F("level", "error"), loggers.F("msg", "line 1\nline 2"), loggers.F("error", "line 1\nline 2")}, pairs[1:])
+}
+
+func TestLogfmtLoggerRegistered(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	l.Register("logfmt", loggers.NewLogfmtLogger(levels.Info, &buf))
+	l.SetStackTrace(logger.StackTraceOptions{Enabled: true})
+
+	l.With("request_id", 7).InfoKV("Request done", "status", 200)
+	l.Debug("Skipped message")
+	l.Error("Failed")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []loggers.Field{
+		loggers.F("level", "info"),
+		loggers.F("msg", "Request done"),
+		loggers.F("request_id", "7"),
+		loggers.F("status", "200"),
+	}, parseLogfmt(t, lines[0])[1:])
+
+	pairs := parseLogfmt(t, lines[1])
+	assert.Equal(t, loggers.StackKey, pairs[3].Key)
+	assert.True(t, strings.HasPrefix(pairs[3].Value.(string), testPackage+"TestLogfmtLoggerRegistered\n\t"))
+}
@@ -0,0 +1,304 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// # Multiple Logs GitHub repository:
+//
+// https://github.com/takecontrolsoft/go_multi_log
+//
+// # Package "admin"
+//
+// This package provides an [http.Handler] to inspect and change
+// the registered loggers of a [logger.MultiLogger] at runtime:
+//
+//	mux.Handle("/admin/loggers/", http.StripPrefix("/admin/loggers", admin.NewHandler(nil)))
+//
+// The handler serves the following requests:
+//   - GET / - lists all registered loggers.
+//   - GET /{key} - returns a single logger.
+//   - PUT /{key} - changes the level or the stopped state of a logger.
+//
+// The default logger with key "" is addressed with the name [DefaultKey].
+// The name is reserved, so while a logger is registered with the key "default",
+// the requests for [DefaultKey] fail with status 409 Conflict.
+// The body of the PUT request is a JSON object with optional properties:
+//
+//	{"level": "debug", "revert_after": "15m", "stopped": false}
+//
+// With "revert_after" the previous level is set again after the given duration,
+// so a temporary Debug level falls back automatically.
+//
+// The handler does not authenticate the requests.
+// Serve it only on an internal address or behind an authentication middleware.
+//
+// # Take Control - software & infrastructure
+//
+// The package is created and maintained by "Take Control - software & infrastructure".
+//
+// Web site: https://takecontrolsoft.eu
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// The name used in the requests for the default logger with key "".
+const DefaultKey = "default"
+
+// [LoggerState] describes a registered logger in the responses of the [Handler].
+// RevertLevel and RevertAt are set while a temporary level is active.
+type LoggerState struct {
+	Key         string           `json:"key"`
+	Type        string           `json:"type"`
+	Level       levels.LogLevel  `json:"level"`
+	Stopped     bool             `json:"stopped"`
+	RevertLevel *levels.LogLevel `json:"revert_level,omitempty"`
+	RevertAt    *time.Time       `json:"revert_at,omitempty"`
+}
+
+// [LoggerUpdate] is the body of the PUT requests. The properties,
+// which are not set, are not changed.
+//   - Level - the new level parsed by [levels.Parse].
+//   - RevertAfter - the duration parsed by [time.ParseDuration], after which
+//     the level before the change is set again. It requires Level.
+//   - Stopped - stops or starts the logger.
+type LoggerUpdate struct {
+	Level       *levels.LogLevel `json:"level"`
+	RevertAfter string           `json:"revert_after"`
+	Stopped     *bool            `json:"stopped"`
+}
+
+// [Handler] is an [http.Handler], which lists the loggers registered in
+// a [logger.MultiLogger] and changes their levels and stopped states.
+// Create it with [NewHandler].
+type Handler struct {
+	logger  *logger.MultiLogger
+	mu      sync.Mutex
+	reverts map[string]*revert
+}
+
+// A pending change back to the level before a temporary level was set.
+type revert struct {
+	logger loggers.LoggerInterface
+	level  levels.LogLevel
+	at     time.Time
+	timer  *time.Timer
+}
+
+// [stoppedReporter] is implemented by the loggers, which report if they are stopped,
+// e.g. all the loggers based on [loggers.LoggerType].
+type stoppedReporter interface {
+	IsStopped() bool
+}
+
+// Returns a [Handler] for the loggers of the given [logger.MultiLogger].
+// The default [logger.MultiLogger] is used when it is nil.
+func NewHandler(l *logger.MultiLogger) *Handler {
+	if l == nil {
+		l = logger.DefaultMultiLogger()
+	}
+	return &Handler{logger: l, reverts: make(map[string]*revert)}
+}
+
+// Serves the requests described in the package documentation.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.Errorf("Method %s is not allowed.", r.Method).Err, http.MethodGet)
+			return
+		}
+		writeJSON(w, http.StatusOK, h.list())
+		return
+	}
+	key, err := url.PathUnescape(path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.Errorf("Logger key is not valid: %w", err).Err)
+		return
+	}
+	if key == DefaultKey {
+		if h.logger.Get(DefaultKey) != nil {
+			writeError(w, http.StatusConflict, errors.Errorf("Logger key %q is reserved for the default logger, register the logger with other key.", DefaultKey).Err)
+			return
+		}
+		key = ""
+	}
+	switch r.Method {
+	case http.MethodGet:
+		state, ok := h.state(key)
+		if !ok {
+			writeError(w, http.StatusNotFound, errors.Errorf("Logger %q is not registered.", key).Err)
+			return
+		}
+		writeJSON(w, http.StatusOK, state)
+	case http.MethodPut:
+		var update LoggerUpdate
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Errorf("Request body is not valid: %w", err).Err)
+			return
+		}
+		state, status, err := h.update(key, update)
+		if err != nil {
+			writeError(w, status, err)
+			return
+		}
+		writeJSON(w, status, state)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("Method %s is not allowed.", r.Method).Err, http.MethodGet, http.MethodPut)
+	}
+}
+
+// Returns the states of all registered loggers sorted by key.
+func (h *Handler) list() []LoggerState {
+	keys := h.logger.Keys()
+	states := make([]LoggerState, 0, len(keys))
+	for _, key := range keys {
+		if state, ok := h.state(key); ok {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+func (h *Handler) state(key string) (LoggerState, bool) {
+	l := h.logger.Get(key)
+	if l == nil {
+		return LoggerState{}, false
+	}
+	state := LoggerState{Key: key, Type: typeName(l), Level: l.GetLevel()}
+	if reporter, ok := l.(stoppedReporter); ok {
+		state.Stopped = reporter.IsStopped()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if pending := h.reverts[key]; pending != nil && pending.logger == l {
+		level, at := pending.level, pending.at
+		state.RevertLevel = &level
+		state.RevertAt = &at
+	}
+	return state, true
+}
+
+// Applies the update to the logger and returns its new state with the HTTP status.
+func (h *Handler) update(key string, update LoggerUpdate) (LoggerState, int, error) {
+	var revertAfter time.Duration
+	if len(update.RevertAfter) > 0 {
+		if update.Level == nil {
+			return LoggerState{}, http.StatusBadRequest, errors.Errorf("Option revert_after requires level.").Err
+		}
+		var err error
+		if revertAfter, err = time.ParseDuration(update.RevertAfter); err != nil || revertAfter <= 0 {
+			return LoggerState{}, http.StatusBadRequest, errors.Errorf("Option revert_after %q is not a positive duration.", update.RevertAfter).Err
+		}
+	}
+	l := h.logger.Get(key)
+	if l == nil {
+		return LoggerState{}, http.StatusNotFound, errors.Errorf("Logger %q is not registered.", key).Err
+	}
+	if update.Level != nil {
+		h.setLevel(key, l, *update.Level, revertAfter)
+	}
+	if update.Stopped != nil {
+		h.mu.Lock()
+		if *update.Stopped {
+			l.Stop()
+		} else {
+			l.Start()
+		}
+		h.mu.Unlock()
+	}
+	state, _ := h.state(key)
+	return state, http.StatusOK, nil
+}
+
+// Sets the level of the logger. With positive "revertAfter" the level
+// before the change is set again after the duration. A pending revert
+// is replaced, but keeps the level set before the first temporary change.
+func (h *Handler) setLevel(key string, l loggers.LoggerInterface, level levels.LogLevel, revertAfter time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	previous := l.GetLevel()
+	if pending := h.reverts[key]; pending != nil {
+		pending.timer.Stop()
+		delete(h.reverts, key)
+		if pending.logger == l {
+			previous = pending.level
+		}
+	}
+	l.SetLevel(level)
+	if revertAfter <= 0 {
+		return
+	}
+	pending := &revert{logger: l, level: previous, at: time.Now().Add(revertAfter)}
+	pending.timer = time.AfterFunc(revertAfter, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.reverts[key] != pending {
+			return
+		}
+		delete(h.reverts, key)
+		pending.logger.SetLevel(pending.level)
+	})
+	h.reverts[key] = pending
+}
+
+// Stops the pending reverts of the temporary levels.
+// The loggers keep their current levels.
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, pending := range h.reverts {
+		pending.timer.Stop()
+		delete(h.reverts, key)
+	}
+	return nil
+}
+
+// Returns the name of the logger type without the package, e.g. "ConsoleLogger".
+func typeName(l loggers.LoggerInterface) string {
+	t := reflect.TypeOf(l)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(t.Name()) == 0 {
+		return t.String()
+	}
+	return t.Name()
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error, allowed ...string) {
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
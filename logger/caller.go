@@ -0,0 +1,97 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// [CallerMode] defines if and how the location of the log call
+// is added to the messages as field with key [loggers.CallerKey].
+type CallerMode int32
+
+const (
+	// The location of the log call is not reported (default).
+	CallerNone CallerMode = iota
+	// The location is reported as file name and line, e.g. "main.go:123".
+	CallerFile
+	// The location is reported as full function name, e.g. "github.com/org/app/db.Open".
+	CallerFunction
+)
+
+// The functions of this package are skipped when looking for the caller.
+var loggerPackage = reflect.TypeOf(multiLog{}).PkgPath() + "."
+
+// The maximum depth of calls inside this package.
+const maxCallerDepth = 16
+
+// Sets how the location of the log calls is reported by the default [MultiLogger].
+//
+//	logger.SetCallerMode(logger.CallerFile)
+//	logger.Info("Message") // 2024/01/14 10:00:00 main.go:12: INFO: [Message]
+func SetCallerMode(mode CallerMode) {
+	DefaultMultiLogger().SetCallerMode(mode)
+}
+
+// Sets how the location of the log calls is reported
+// by this [MultiLogger] and its children.
+func (l *MultiLogger) SetCallerMode(mode CallerMode) {
+	l.m.callerMode.Store(int32(mode))
+}
+
+// Returns the caller field for the first function outside this package
+// or false if the caller is not reported.
+func (l *MultiLogger) callerField() (loggers.Field, bool) {
+	mode := CallerMode(l.m.callerMode.Load())
+	if mode == CallerNone {
+		return loggers.Field{}, false
+	}
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, loggerPackage) {
+			return callerFrameField(mode, frame)
+		}
+		if !more {
+			return loggers.Field{}, false
+		}
+	}
+}
+
+// Returns the caller field for the program counter of a log call.
+func (l *MultiLogger) callerFieldPC(pc uintptr) (loggers.Field, bool) {
+	mode := CallerMode(l.m.callerMode.Load())
+	if mode == CallerNone || pc == 0 {
+		return loggers.Field{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return callerFrameField(mode, frame)
+}
+
+func callerFrameField(mode CallerMode, frame runtime.Frame) (loggers.Field, bool) {
+	if mode == CallerFunction {
+		return loggers.F(loggers.CallerKey, frame.Function), true
+	}
+	return loggers.F(loggers.CallerKey, filepath.Base(frame.File)+":"+strconv.Itoa(frame.Line)), true
+}
@@ -0,0 +1,480 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+	"gopkg.in/yaml.v3"
+)
+
+// [Config] describes the loggers registered by [Configure].
+//
+//	level: warning
+//	loggers:
+//	  app_file:
+//	    type: file
+//	    level: debug
+//	    format: json
+//	    file:
+//	      directory: /var/log/app
+//	      prefix: app
+//	      extension: .log
+//	      max_size: 10485760
+//	      rollover: daily
+//	      max_age: 168h
+//	  console:
+//	    type: console
+//	    format: "{time:15:04:05} {level} {message} {fields}"
+type Config struct {
+	// The level of the default console logger. It is not changed by the first
+	// configuration when empty and it is reset to "info" when it is removed on reload.
+	Level LevelConfig `json:"level" yaml:"level"`
+	// Stops the default console logger, e.g. when other console logger is configured.
+	DisableDefault bool `json:"disable_default" yaml:"disable_default"`
+	// The loggers by key. The empty key of the default logger is not allowed.
+	Loggers map[string]LoggerConfig `json:"loggers" yaml:"loggers"`
+}
+
+// [LoggerConfig] describes a single logger:
+//   - Type - "console" or "file".
+//   - Level - the log level name or number (see [levels.Parse]), "info" is used when empty.
+//   - Format - "text" (default), "json", "logfmt", "pretty" or a template
+//     with placeholders (see [loggers.Template]).
+//   - Color - "auto" (default), "always" or "never" for the "pretty" format.
+//     The files are never terminals, so "auto" prints colors only on console.
+//   - File - the options of the "file" loggers.
+type LoggerConfig struct {
+	Type   string      `json:"type" yaml:"type"`
+	Level  LevelConfig `json:"level" yaml:"level"`
+	Format string      `json:"format" yaml:"format"`
+	Color  string      `json:"color" yaml:"color"`
+	File   *FileConfig `json:"file" yaml:"file"`
+}
+
+// [LevelConfig] is a log level given in the configuration as a name
+// or a number (see [levels.Parse]). It is parsed when the configuration
+// is applied, so the invalid levels are reported with the keys of the loggers
+// together with the other problems.
+type LevelConfig string
+
+// Keeps the JSON string or number as the level.
+func (level *LevelConfig) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*level = LevelConfig(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return errors.Errorf("Log level must be a string or a number: %s", data).Err
+	}
+	*level = LevelConfig(number)
+	return nil
+}
+
+// Keeps the YAML string or number as the level.
+func (level *LevelConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return errors.Errorf("Log level must be a string or a number at line %d.", node.Line).Err
+	}
+	*level = LevelConfig(node.Value)
+	return nil
+}
+
+// Returns the parsed level or "info" when the level is empty.
+func (level LevelConfig) parse() (levels.LogLevel, error) {
+	if len(level) == 0 {
+		return levels.Info, nil
+	}
+	return levels.Parse(string(level))
+}
+
+// [FileConfig] describes the [loggers.FileOptions] of a file logger.
+// The prefix "mLog" and the extension ".log" are used when they are empty.
+// The durations are given as strings parsed by [time.ParseDuration], e.g. "24h".
+// The rollover is "none" (default), "hourly" or "daily".
+type FileConfig struct {
+	Directory     string `json:"directory" yaml:"directory"`
+	Prefix        string `json:"prefix" yaml:"prefix"`
+	Extension     string `json:"extension" yaml:"extension"`
+	PerGoroutine  bool   `json:"per_goroutine" yaml:"per_goroutine"`
+	TagGoroutine  bool   `json:"tag_goroutine" yaml:"tag_goroutine"`
+	MaxSize       int64  `json:"max_size" yaml:"max_size"`
+	Rollover      string `json:"rollover" yaml:"rollover"`
+	MaxBackups    int    `json:"max_backups" yaml:"max_backups"`
+	MaxAge        string `json:"max_age" yaml:"max_age"`
+	Compress      bool   `json:"compress" yaml:"compress"`
+	BufferSize    int    `json:"buffer_size" yaml:"buffer_size"`
+	FlushInterval string `json:"flush_interval" yaml:"flush_interval"`
+	MaxOpenFiles  int    `json:"max_open_files" yaml:"max_open_files"`
+}
+
+// Parses the configuration in JSON format.
+// Unknown keys are reported as errors.
+func ParseJSONConfig(data []byte) (*Config, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, errors.Errorf("Parsing JSON configuration failed: %w", err).Err
+	}
+	return config, nil
+}
+
+// Parses the configuration in YAML format.
+// Unknown keys are reported as errors.
+func ParseYAMLConfig(data []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, errors.Errorf("Parsing YAML configuration failed: %w", err).Err
+	}
+	return config, nil
+}
+
+// Reads the configuration from a file with extension ".json", ".yaml" or ".yml".
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Errorf("Reading configuration failed: %w", err).Err
+	}
+	return parseConfig(path, data)
+}
+
+// Parses the configuration in the format given by the extension of the file.
+func parseConfig(path string, data []byte) (*Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseJSONConfig(data)
+	case ".yaml", ".yml":
+		return ParseYAMLConfig(data)
+	default:
+		return nil, errors.Errorf("Configuration file %q must have extension .json, .yaml or .yml.", path).Err
+	}
+}
+
+// Builds the loggers described in the configuration and registers them
+// in the default [MultiLogger]. See [MultiLogger.Configure].
+func Configure(config *Config) error {
+	return DefaultMultiLogger().Configure(config)
+}
+
+// Reads the configuration from the file and registers the loggers
+// in the default [MultiLogger]. See [LoadConfig] and [MultiLogger.Configure].
+func ConfigureFromFile(path string) error {
+	return DefaultMultiLogger().ConfigureFromFile(path)
+}
+
+// Reads the configuration from the file and registers the loggers
+// in this [MultiLogger]. See [LoadConfig] and [MultiLogger.Configure].
+func (l *MultiLogger) ConfigureFromFile(path string) error {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return l.Configure(config)
+}
+
+// Builds the loggers described in the configuration and registers them
+// in this [MultiLogger] in one step. The loggers registered with the same
+// keys are replaced, flushed and closed. Nothing is changed if the
+// configuration is not valid and the returned error describes all the problems.
+func (l *MultiLogger) Configure(config *Config) error {
+	return l.applyConfig(nil, config)
+}
+
+// Applies the configuration, which follows the "previous" applied configuration.
+// The loggers with unchanged configuration are kept, the changed levels are set
+// to the registered loggers and the loggers, which are not configured anymore,
+// are unregistered and closed.
+// The replaced loggers are closed after the log calls using them are finished,
+// so no messages are lost during the change.
+func (l *MultiLogger) applyConfig(previous, config *Config) error {
+	built, err := buildLoggers(previous, config, l.m.snapshot())
+	if err != nil {
+		return err
+	}
+	var closing []loggers.LoggerInterface
+	replaced, _ := l.m.replace(func(r registry) error {
+		if previous != nil {
+			for key := range previous.Loggers {
+				if _, ok := config.Loggers[key]; !ok && r[key] != nil {
+					closing = append(closing, r[key])
+					delete(r, key)
+				}
+			}
+		}
+		for key, logger := range built {
+			if old := r[key]; old != nil {
+				closing = append(closing, old)
+			}
+			r[key] = logger
+		}
+		// The changed levels of the kept loggers are set in place.
+		for key, loggerConfig := range config.Loggers {
+			if _, rebuilt := built[key]; rebuilt || previous == nil || r[key] == nil {
+				continue
+			}
+			if old, ok := previous.Loggers[key]; ok && old.level() != loggerConfig.level() {
+				r[key].SetLevel(loggerConfig.level())
+			}
+		}
+		return nil
+	})
+	l.configureDefault(previous, config)
+	if len(closing) > 0 {
+		l.m.drain(replaced)
+		for _, logger := range closing {
+			closeLogger(logger)
+		}
+	}
+	return nil
+}
+
+// Sets the level of the default logger and stops it if it is disabled.
+// Only the changes to the "previous" configuration are applied, so the default
+// logger is started again only when it is not disabled anymore.
+// The level is reset to "info" when it is removed from the configuration like
+// the levels of the other loggers. The configuration is validated by [buildLoggers].
+func (l *MultiLogger) configureDefault(previous, config *Config) {
+	defaultLogger := l.DefaultLogger()
+	if defaultLogger == nil {
+		return
+	}
+	level, _ := config.Level.parse()
+	if previous == nil && len(config.Level) > 0 {
+		defaultLogger.SetLevel(level)
+	} else if previous != nil {
+		if previousLevel, _ := previous.Level.parse(); previousLevel != level {
+			defaultLogger.SetLevel(level)
+		}
+	}
+	if (previous == nil && !config.DisableDefault) || (previous != nil && previous.DisableDefault == config.DisableDefault) {
+		return
+	}
+	if config.DisableDefault {
+		defaultLogger.Stop()
+	} else {
+		defaultLogger.Start()
+	}
+}
+
+// Builds the loggers of the configuration, which are not registered
+// with the same configuration in the "previous" applied configuration.
+// The loggers, which differ only in the level, are not built again.
+// Returns the errors of all invalid loggers joined together.
+func buildLoggers(previous, config *Config, registered registry) (map[string]loggers.LoggerInterface, error) {
+	if config == nil {
+		return nil, errors.Errorf("Configuration is missing.").Err
+	}
+	var errs []error
+	if _, err := config.Level.parse(); err != nil {
+		errs = append(errs, errors.Errorf("Default logger: %w", err).Err)
+	}
+	keys := make([]string, 0, len(config.Loggers))
+	for key := range config.Loggers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	built := make(map[string]loggers.LoggerInterface, len(keys))
+	for _, key := range keys {
+		if len(key) == 0 {
+			errs = append(errs, errors.Errorf("Empty key is not allowed for configured loggers.").Err)
+			continue
+		}
+		if _, err := config.Loggers[key].Level.parse(); err != nil {
+			errs = append(errs, errors.Errorf("Logger %q: %w", key, err).Err)
+			continue
+		}
+		if previous != nil && registered[key] != nil {
+			if old, ok := previous.Loggers[key]; ok && sameExceptLevel(old, config.Loggers[key]) {
+				continue
+			}
+		}
+		logger, err := buildLogger(config.Loggers[key])
+		if err != nil {
+			errs = append(errs, errors.Errorf("Logger %q: %w", key, err).Err)
+			continue
+		}
+		built[key] = logger
+	}
+	if len(errs) > 0 {
+		for _, logger := range built {
+			closeLogger(logger)
+		}
+		return nil, errors.Join(errs...)
+	}
+	return built, nil
+}
+
+// Reports if the configurations of a logger differ at most in the level,
+// which can be changed without building the logger again.
+func sameExceptLevel(old, config LoggerConfig) bool {
+	old.Level, config.Level = "", ""
+	return reflect.DeepEqual(old, config)
+}
+
+// Returns the configured level or "info" when it is empty.
+// The level is validated by [buildLoggers].
+func (config LoggerConfig) level() levels.LogLevel {
+	level, _ := config.Level.parse()
+	return level
+}
+
+func buildLogger(config LoggerConfig) (loggers.LoggerInterface, error) {
+	level := config.level()
+	formatter, err := buildFormatter(config)
+	if err != nil {
+		return nil, err
+	}
+	switch config.Type {
+	case "console":
+		if config.File != nil {
+			return nil, errors.Errorf("File options are allowed only for type \"file\".").Err
+		}
+		if config.Format == "pretty" {
+			color, _ := parseColorMode(config.Color)
+			return loggers.NewPrettyConsoleLogger(level, color), nil
+		}
+		if formatter == nil {
+			return loggers.NewConsoleLogger(level, ""), nil
+		}
+		return loggers.NewConsoleLoggerFormatter(level, formatter), nil
+	case "file":
+		options, err := config.File.options()
+		if err != nil {
+			return nil, err
+		}
+		if formatter == nil {
+			return loggers.NewFileLogger(level, "", options), nil
+		}
+		return loggers.NewFileLoggerFormatter(level, formatter, options), nil
+	case "":
+		return nil, errors.Errorf("Type is missing, use \"console\" or \"file\".").Err
+	default:
+		return nil, errors.Errorf("Unknown type %q, use \"console\" or \"file\".", config.Type).Err
+	}
+}
+
+// Returns the formatter of the logger or nil for the default text format.
+func buildFormatter(config LoggerConfig) (loggers.FormatterInterface, error) {
+	if len(config.Color) > 0 && config.Format != "pretty" {
+		return nil, errors.Errorf("Color is allowed only for format \"pretty\".").Err
+	}
+	switch config.Format {
+	case "", "text":
+		return nil, nil
+	case "json":
+		return loggers.JSONFormatter{}, nil
+	case "logfmt":
+		return loggers.LogfmtFormatter{}, nil
+	case "pretty":
+		color, err := parseColorMode(config.Color)
+		if err != nil {
+			return nil, err
+		}
+		// Files are never terminals, so only ColorAlways prints colors.
+		// The console loggers resolve the color mode in [loggers.NewPrettyConsoleLogger].
+		return loggers.PrettyFormatter{Color: color == loggers.ColorAlways}, nil
+	}
+	if !strings.Contains(config.Format, "{") {
+		return nil, errors.Errorf("Unknown format %q, use \"text\", \"json\", \"logfmt\", \"pretty\" or a template.", config.Format).Err
+	}
+	return loggers.ParseTemplate(config.Format)
+}
+
+func parseColorMode(color string) (loggers.ColorMode, error) {
+	switch color {
+	case "", "auto":
+		return loggers.ColorAuto, nil
+	case "always":
+		return loggers.ColorAlways, nil
+	case "never":
+		return loggers.ColorNever, nil
+	default:
+		return loggers.ColorAuto, errors.Errorf("Unknown color %q, use \"auto\", \"always\" or \"never\".", color).Err
+	}
+}
+
+// Returns the file options. Default options are used when the configuration is nil
+// and the default prefix and extension are used when they are empty.
+func (config *FileConfig) options() (loggers.FileOptions, error) {
+	if config == nil {
+		return loggers.FileOptions{FilePrefix: "mLog", FileExtension: ".log"}, nil
+	}
+	options := loggers.FileOptions{
+		Directory:     config.Directory,
+		FilePrefix:    config.Prefix,
+		FileExtension: config.Extension,
+		PerGoroutine:  config.PerGoroutine,
+		TagGoroutine:  config.TagGoroutine,
+		MaxSize:       config.MaxSize,
+		MaxBackups:    config.MaxBackups,
+		Compress:      config.Compress,
+		BufferSize:    config.BufferSize,
+		MaxOpenFiles:  config.MaxOpenFiles,
+	}
+	if len(options.FilePrefix) == 0 {
+		options.FilePrefix = "mLog"
+	}
+	if len(options.FileExtension) == 0 {
+		options.FileExtension = ".log"
+	}
+	var errs []error
+	switch config.Rollover {
+	case "", "none":
+		options.Rollover = loggers.RolloverNone
+	case "hourly":
+		options.Rollover = loggers.RolloverHourly
+	case "daily":
+		options.Rollover = loggers.RolloverDaily
+	default:
+		errs = append(errs, errors.Errorf("Unknown rollover %q, use \"none\", \"hourly\" or \"daily\".", config.Rollover).Err)
+	}
+	var err error
+	if options.MaxAge, err = parseDuration("max_age", config.MaxAge); err != nil {
+		errs = append(errs, err)
+	}
+	if options.FlushInterval, err = parseDuration("flush_interval", config.FlushInterval); err != nil {
+		errs = append(errs, err)
+	}
+	return options, errors.Join(errs...)
+}
+
+func parseDuration(name, value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Errorf("Option %s is not valid: %w", name, err).Err
+	}
+	return duration, nil
+}
@@ -0,0 +1,181 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// [ContextExtractor] returns the request-scoped fields stored in the context,
+// for example trace id, span id or user. It returns nil if the context
+// does not contain such values.
+//
+//	logger.RegisterContextExtractor(func(ctx context.Context) []loggers.Field {
+//		if traceId, ok := ctx.Value(traceKey{}).(string); ok {
+//			return []loggers.Field{loggers.F("trace_id", traceId)}
+//		}
+//		return nil
+//	})
+type ContextExtractor func(ctx context.Context) []loggers.Field
+
+type contextKey struct{}
+
+// Returns a copy of the context, which carries the given [MultiLogger].
+func NewContext(ctx context.Context, l *MultiLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// Returns the [MultiLogger] stored in the context by [NewContext]
+// or the default [MultiLogger] if the context does not carry one.
+func FromContext(ctx context.Context) *MultiLogger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(*MultiLogger); ok && l != nil {
+			return l
+		}
+	}
+	return DefaultMultiLogger()
+}
+
+// Registers an extractor of fields from the context
+// used by the functions DebugCtx ... FatalCtx of the default [MultiLogger].
+func RegisterContextExtractor(extractor ContextExtractor) {
+	DefaultMultiLogger().RegisterContextExtractor(extractor)
+}
+
+// Registers an extractor of fields from the context used by the functions
+// DebugCtx ... FatalCtx and the [SlogHandler] of this [MultiLogger] and its children.
+// It is safe to register extractors while other goroutines are logging.
+func (l *MultiLogger) RegisterContextExtractor(extractor ContextExtractor) {
+	if extractor == nil {
+		return
+	}
+	m := l.m
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var next []ContextExtractor
+	if current := m.extractors.Load(); current != nil {
+		next = append(next, *current...)
+	}
+	next = append(next, extractor)
+	m.extractors.Store(&next)
+}
+
+// Returns the fields of all registered extractors followed by the given fields.
+func (l *MultiLogger) contextFields(ctx context.Context, fields []loggers.Field) []loggers.Field {
+	extractors := l.m.extractors.Load()
+	if ctx == nil || extractors == nil {
+		return fields
+	}
+	var all []loggers.Field
+	for _, extractor := range *extractors {
+		all = append(all, extractor(ctx)...)
+	}
+	if len(all) == 0 {
+		return fields
+	}
+	return append(all, fields...)
+}
+
+func (l *MultiLogger) logCtxAll(ctx context.Context, level levels.LogLevel, msg string, keysAndValues ...any) {
+	fields := l.entryFields(level, nil, l.contextFields(ctx, loggers.Fields(keysAndValues...)))
+	l.logFieldsAll(_logKV, level, msg, fields)
+	if level == levels.Fatal {
+		l.m.fatal(msg)
+	}
+}
+
+// Log message with the fields from the context in Debug level
+// using the [MultiLogger] stored in the context (see [FromContext]).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func DebugCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).logCtxAll(ctx, levels.Debug, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Trace level
+// using the [MultiLogger] stored in the context (see [FromContext]).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func TraceCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).logCtxAll(ctx, levels.Trace, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Info level
+// using the [MultiLogger] stored in the context (see [FromContext]).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func InfoCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).logCtxAll(ctx, levels.Info, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Warning level
+// using the [MultiLogger] stored in the context (see [FromContext]).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func WarningCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).logCtxAll(ctx, levels.Warning, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Error level
+// using the [MultiLogger] stored in the context (see [FromContext]).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func ErrorCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).logCtxAll(ctx, levels.Error, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Fatal level
+// using the [MultiLogger] stored in the context (see [FromContext])
+// and call the [FatalHandler] (Panic by default).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func FatalCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).logCtxAll(ctx, levels.Fatal, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Debug level.
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) DebugCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	l.logCtxAll(ctx, levels.Debug, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Trace level.
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) TraceCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	l.logCtxAll(ctx, levels.Trace, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Info level.
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) InfoCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	l.logCtxAll(ctx, levels.Info, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Warning level.
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) WarningCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	l.logCtxAll(ctx, levels.Warning, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Error level.
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) ErrorCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	l.logCtxAll(ctx, levels.Error, msg, keysAndValues...)
+}
+
+// Log message with the fields from the context in Fatal level
+// and call the [FatalHandler] (Panic by default).
+// The additional fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) FatalCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	l.logCtxAll(ctx, levels.Fatal, msg, keysAndValues...)
+}
@@ -0,0 +1,85 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// The environment variable with the level of the default logger.
+// The level of a registered logger is set by the variable
+// with the logger key appended, see [LevelEnvName].
+const LevelEnv = "GOMULTILOG_LEVEL"
+
+// Returns the name of the environment variable with the level of the logger
+// registered with the given key: [LevelEnv] followed by "_" and the key
+// in upper case with all characters except letters and digits replaced by "_".
+// The default logger (empty key) uses [LevelEnv].
+//
+//	logger.LevelEnvName("json-file") // GOMULTILOG_LEVEL_JSON_FILE
+func LevelEnvName(key string) string {
+	if len(key) == 0 {
+		return LevelEnv
+	}
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+	return LevelEnv + "_" + strings.ToUpper(name)
+}
+
+// Sets the levels of the default logger and the registered loggers from the
+// environment variables named by [LevelEnvName], e.g. GOMULTILOG_LEVEL=debug
+// and GOMULTILOG_LEVEL_JSON_FILE=warning. The values are parsed by [levels.Parse].
+// The loggers without variables keep their levels.
+func SetLevelsFromEnv() error {
+	return DefaultMultiLogger().SetLevelsFromEnv()
+}
+
+// Sets the levels of the loggers registered in this [MultiLogger]
+// from the environment variables named by [LevelEnvName].
+// All valid levels are set and the errors for invalid values are joined together.
+func (l *MultiLogger) SetLevelsFromEnv() error {
+	registered := l.m.snapshot()
+	keys := make([]string, 0, len(registered))
+	for key := range registered {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		name := LevelEnvName(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		level, err := levels.Parse(value)
+		if err != nil {
+			errs = append(errs, errors.Errorf("Environment variable %s is not valid: %w", name, err).Err)
+			continue
+		}
+		registered[key].SetLevel(level)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// [FatalHandler] is called after a message in Fatal level is logged
+// by all the loggers and the buffered messages are written. The argument is the logged object for [Fatal]
+// and the formatted message for [FatalF] and [FatalKV].
+// If the handler returns, the execution continues after the log call.
+type FatalHandler func(arg any)
+
+// The time given to the loggers to be flushed and closed by [ExitOnFatal].
+const fatalShutdownTimeout = 5 * time.Second
+
+// [FatalHandler], which calls Panic with the logged message.
+// It is used by default.
+func PanicOnFatal(arg any) {
+	panic(arg)
+}
+
+// Returns a [FatalHandler], which flushes and closes all registered
+// loggers using [Shutdown] and then exits the process with the given code.
+func ExitOnFatal(code int) FatalHandler {
+	return DefaultMultiLogger().ExitOnFatal(code)
+}
+
+// Returns a [FatalHandler], which flushes and closes all loggers
+// registered in this [MultiLogger] using [MultiLogger.Shutdown]
+// and then exits the process with the given code.
+func (l *MultiLogger) ExitOnFatal(code int) FatalHandler {
+	return func(arg any) {
+		ctx, cancel := context.WithTimeout(context.Background(), fatalShutdownTimeout)
+		defer cancel()
+		l.Shutdown(ctx)
+		os.Exit(code)
+	}
+}
+
+// Sets the handler called after logging a message in Fatal level.
+// Use [PanicOnFatal], [ExitOnFatal] or a custom function.
+// Setting nil restores the default [PanicOnFatal].
+func SetFatalHandler(handler FatalHandler) {
+	DefaultMultiLogger().SetFatalHandler(handler)
+}
+
+// Sets the handler called after logging a message in Fatal level
+// with this [MultiLogger]. Setting nil restores the default [PanicOnFatal].
+func (l *MultiLogger) SetFatalHandler(handler FatalHandler) {
+	if handler == nil {
+		handler = PanicOnFatal
+	}
+	l.m.fatalHandler.Store(&handler)
+}
+
+func (m *multiLog) fatal(arg any) {
+	m.flush()
+	handler := m.fatalHandler.Load()
+	if handler == nil {
+		PanicOnFatal(arg)
+		return
+	}
+	(*handler)(arg)
+}
+
+// Writes the buffered messages of the registered loggers implementing
+// [loggers.FlusherInterface], so the Fatal message is stored even if
+// the handler panics. The loggers are not closed.
+func (m *multiLog) flush() {
+	for _, logger := range m.snapshot() {
+		if flusher, ok := logger.(loggers.FlusherInterface); ok {
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,428 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// registry is an immutable snapshot of the registered loggers.
+// It is never modified after being published, so it can be
+// iterated without holding any lock.
+type registry map[string]loggers.LoggerInterface
+
+// generation is a published [registry] with the number of log calls,
+// which are still using it. Replaced loggers are closed only
+// after the log calls using them are finished (see [multiLog.drain]).
+type generation struct {
+	loggers registry
+	active  atomic.Int64
+}
+
+// multiLog keeps the registered loggers as a copy-on-write snapshot.
+// Writers (register and unregister) are serialized by "lock" and
+// publish a new snapshot, while readers load the current snapshot atomically.
+type multiLog struct {
+	lock               sync.Mutex
+	registered_loggers atomic.Pointer[generation]
+	closed             atomic.Bool
+	fatalHandler       atomic.Pointer[FatalHandler]
+	extractors         atomic.Pointer[[]ContextExtractor]
+	callerMode         atomic.Int32
+	stackTrace         atomic.Pointer[StackTraceOptions]
+	namedLevels        atomic.Pointer[map[string]levels.LogLevel]
+}
+
+// [MultiLogger] logs the messages in all the loggers registered in it.
+// Each instance has its own registry of loggers, fatal handler and shutdown state,
+// so libraries and tests can use loggers isolated from the package level functions.
+//
+//	l := logger.New()
+//	l.Register("file", loggers.NewFileLoggerDefault())
+//	l.Info("Message")
+//
+// Child loggers created by [MultiLogger.With] share the registry of their parent
+// and add their bound fields to each message.
+type MultiLogger struct {
+	m      *multiLog
+	fields []loggers.Field
+	name   string
+}
+
+// Returns a new instance of [MultiLogger] with its own registry,
+// which contains a default [loggers.ConsoleLogger] with key "".
+func New() *MultiLogger {
+	m := &multiLog{}
+	m.registered_loggers.Store(&generation{loggers: registry{
+		"": loggers.NewConsoleLoggerDefault(),
+	}})
+	return &MultiLogger{m: m}
+}
+
+// Returns a child [MultiLogger], which adds the given fields to each message
+// logged in all the registered loggers. The fields are given as alternating
+// keys and values or as [loggers.Field] and follow the fields of the parent.
+// The child shares the registry, the fatal handler and the shutdown of its parent.
+//
+//	reqLog := l.With("request_id", id, "tenant", tenant)
+//	reqLog.Info("Request started")
+func (l *MultiLogger) With(keysAndValues ...any) *MultiLogger {
+	fields := loggers.Fields(keysAndValues...)
+	if len(fields) == 0 {
+		return l
+	}
+	return &MultiLogger{m: l.m, fields: l.withFields(fields), name: l.name}
+}
+
+// Returns the bound fields followed by the given fields.
+// The bound fields are never modified, because they are shared with the children.
+func (l *MultiLogger) withFields(fields []loggers.Field) []loggers.Field {
+	if len(l.fields) == 0 {
+		return fields
+	}
+	all := make([]loggers.Field, 0, len(l.fields)+len(fields))
+	all = append(all, l.fields...)
+	return append(all, fields...)
+}
+
+// Returns the fields of a single message in the given level, which logs
+// the object "arg": the caller (if reported), the bound fields,
+// the given fields and the stack trace (if enabled).
+func (l *MultiLogger) entryFields(level levels.LogLevel, arg any, fields []loggers.Field) []loggers.Field {
+	caller, withCaller := l.callerField()
+	stack, withStack := l.stackField(level, arg, fields)
+	if !withCaller && !withStack {
+		return l.withFields(fields)
+	}
+	all := make([]loggers.Field, 0, 2+len(l.fields)+len(fields))
+	if withCaller {
+		all = append(all, caller)
+	}
+	all = append(all, l.fields...)
+	all = append(all, fields...)
+	if withStack {
+		all = append(all, stack)
+	}
+	return all
+}
+
+// Returns the current snapshot of registered loggers.
+func (m *multiLog) snapshot() registry {
+	return m.registered_loggers.Load().loggers
+}
+
+// Returns the loggers, which receive the messages.
+// No loggers receive messages after [Shutdown].
+func (m *multiLog) receivers() registry {
+	if m.closed.Load() {
+		return nil
+	}
+	return m.snapshot()
+}
+
+// Publishes a copy of the current snapshot changed by "update".
+// The "update" function is called while holding the lock and
+// can reject the change by returning an error.
+func (m *multiLog) modify(update func(r registry) error) error {
+	_, err := m.replace(update)
+	return err
+}
+
+// Publishes a copy of the current snapshot changed by "update" like
+// [multiLog.modify] and returns the replaced generation.
+func (m *multiLog) replace(update func(r registry) error) (*generation, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	current := m.registered_loggers.Load()
+	next := make(registry, len(current.loggers)+1)
+	for key, logger := range current.loggers {
+		next[key] = logger
+	}
+	if err := update(next); err != nil {
+		return nil, err
+	}
+	m.registered_loggers.Store(&generation{loggers: next})
+	return current, nil
+}
+
+// Calls "fn" for each logger, which receives the messages.
+// The loggers are not closed by [MultiLogger.Configure]
+// or by the config watcher before "fn" returns.
+func (m *multiLog) forEach(fn func(logger loggers.LoggerInterface)) {
+	if m.closed.Load() {
+		return
+	}
+	g := m.acquire()
+	defer g.active.Add(-1)
+	// Shutdown may be called meanwhile and it waits only
+	// for the log calls, which acquired the generation before.
+	if m.closed.Load() {
+		return
+	}
+	for _, logger := range g.loggers {
+		fn(logger)
+	}
+}
+
+// Returns the current generation marked as used by one more log call.
+// If the generation is replaced meanwhile, the new one is used, so
+// [multiLog.drain] never misses a log call using the old generation.
+func (m *multiLog) acquire() *generation {
+	for {
+		g := m.registered_loggers.Load()
+		g.active.Add(1)
+		if g == m.registered_loggers.Load() {
+			return g
+		}
+		g.active.Add(-1)
+	}
+}
+
+// Waits until the log calls using the given replaced generation are finished.
+func (m *multiLog) drain(g *generation) {
+	for g.active.Load() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type fnLog func(logger loggers.LoggerInterface, level levels.LogLevel, arg any)
+type fnLogF func(logger loggers.LoggerInterface, format string, level levels.LogLevel, args ...interface{})
+type fnLogKV func(logger loggers.LoggerInterface, level levels.LogLevel, msg string, fields []loggers.Field)
+
+func _log(logger loggers.LoggerInterface, level levels.LogLevel, arg any) {
+	logger.Log(level, arg)
+}
+
+func _logF(logger loggers.LoggerInterface, format string, level levels.LogLevel, args ...interface{}) {
+	logger.LogF(level, format, args...)
+}
+
+// Passes the fields to loggers implementing [loggers.FieldLoggerInterface].
+// Other loggers receive the fields rendered into the message.
+func _logKV(logger loggers.LoggerInterface, level levels.LogLevel, msg string, fields []loggers.Field) {
+	if fieldLogger, ok := logger.(loggers.FieldLoggerInterface); ok {
+		fieldLogger.LogKV(level, msg, fields...)
+		return
+	}
+	if fields := loggers.PlainFields(fields); len(fields) > 0 {
+		msg = msg + " " + loggers.FormatFields(fields)
+	}
+	logger.Log(level, msg)
+}
+
+// Messages of loggers with bound fields or reported caller
+// are passed to the registered loggers as messages with fields.
+func (l *MultiLogger) logAll(fn fnLog, level levels.LogLevel, arg any) {
+	if fields := l.entryFields(level, arg, nil); len(fields) > 0 {
+		if err, ok := arg.(error); ok {
+			fields = append(fields, loggers.ErrorField(err))
+		}
+		l.logFieldsAll(_logKV, level, fmt.Sprint(arg), fields)
+	} else {
+		l.m.forEach(func(logger loggers.LoggerInterface) {
+			fn(logger, level, arg)
+		})
+	}
+	if level == levels.Fatal {
+		l.m.fatal(arg)
+	}
+}
+
+func (l *MultiLogger) logFAll(fn fnLogF, format string, level levels.LogLevel, args ...interface{}) {
+	if fields := l.entryFields(level, nil, nil); len(fields) > 0 {
+		l.logFieldsAll(_logKV, level, fmt.Sprintf(format, args...), fields)
+	} else {
+		l.m.forEach(func(logger loggers.LoggerInterface) {
+			fn(logger, format, level, args...)
+		})
+	}
+	if level == levels.Fatal {
+		l.m.fatal(fmt.Sprintf(format, args...))
+	}
+}
+
+func (l *MultiLogger) logKVAll(fn fnLogKV, level levels.LogLevel, msg string, keysAndValues ...any) {
+	l.logFieldsAll(fn, level, msg, l.entryFields(level, nil, loggers.Fields(keysAndValues...)))
+	if level == levels.Fatal {
+		l.m.fatal(msg)
+	}
+}
+
+// The messages of named loggers with overridden level (see [MultiLogger.SetNamedLevel])
+// are filtered by the overridden level instead of the levels of the loggers.
+func (l *MultiLogger) logFieldsAll(fn fnLogKV, level levels.LogLevel, msg string, fields []loggers.Field) {
+	if namedLevel, ok := l.namedLevel(); ok {
+		if level >= namedLevel {
+			l.m.forEach(func(logger loggers.LoggerInterface) {
+				_logKVOverride(logger, level, msg, fields)
+			})
+		}
+		return
+	}
+	l.m.forEach(func(logger loggers.LoggerInterface) {
+		fn(logger, level, msg, fields)
+	})
+}
+
+// Register an instance of an additional logger
+// that implements [loggers.LoggerInterface].
+// It is safe to register loggers while other goroutines are logging.
+func (l *MultiLogger) Register(key string, logger loggers.LoggerInterface) error {
+	if len(key) == 0 {
+		return errors.Errorf("Empty key is not allowed for registering loggers.").Err
+	}
+	return l.m.modify(func(r registry) error {
+		r[key] = logger
+		return nil
+	})
+}
+
+// Unregister an instance of logger by key.
+// It is safe to unregister loggers while other goroutines are logging.
+func (l *MultiLogger) Unregister(key string) error {
+	return l.m.modify(func(r registry) error {
+		if r[key] == nil {
+			return errors.Errorf("A logger for given key does not exists.").Err
+		}
+		delete(r, key)
+		return nil
+	})
+}
+
+// Return a registered logger instance by key.
+func (l *MultiLogger) Get(key string) loggers.LoggerInterface {
+	return l.m.snapshot()[key]
+}
+
+// Returns the sorted keys of the registered loggers including the key "" of the default logger.
+func (l *MultiLogger) Keys() []string {
+	registered := l.m.snapshot()
+	keys := make([]string, 0, len(registered))
+	for key := range registered {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Return the default instance of [loggers.ConsoleLogger] of this [MultiLogger].
+func (l *MultiLogger) DefaultLogger() loggers.LoggerInterface {
+	return l.Get("")
+}
+
+// Log object in Debug level.
+func (l *MultiLogger) Debug(arg any) {
+	l.logAll(_log, levels.Debug, arg)
+}
+
+// Log object in Trace level.
+func (l *MultiLogger) Trace(arg any) {
+	l.logAll(_log, levels.Trace, arg)
+}
+
+// Log object in Info level.
+func (l *MultiLogger) Info(arg any) {
+	l.logAll(_log, levels.Info, arg)
+}
+
+// Log object in Warning level.
+func (l *MultiLogger) Warning(arg any) {
+	l.logAll(_log, levels.Warning, arg)
+}
+
+// Log object in Error level.
+func (l *MultiLogger) Error(arg any) {
+	l.logAll(_log, levels.Error, arg)
+}
+
+// Log object in Fatal level and call the [FatalHandler] (Panic by default).
+func (l *MultiLogger) Fatal(arg any) {
+	l.logAll(_log, levels.Fatal, arg)
+}
+
+// Log objects using format string in Debug level.
+func (l *MultiLogger) DebugF(format string, args ...interface{}) {
+	l.logFAll(_logF, format, levels.Debug, args...)
+}
+
+// Log objects using format string in Trace level.
+func (l *MultiLogger) TraceF(format string, args ...interface{}) {
+	l.logFAll(_logF, format, levels.Trace, args...)
+}
+
+// Log objects using format string in Info level.
+func (l *MultiLogger) InfoF(format string, args ...interface{}) {
+	l.logFAll(_logF, format, levels.Info, args...)
+}
+
+// Log objects using format string in Warning level.
+func (l *MultiLogger) WarningF(format string, args ...interface{}) {
+	l.logFAll(_logF, format, levels.Warning, args...)
+}
+
+// Log objects using format string in Error level.
+func (l *MultiLogger) ErrorF(format string, args ...interface{}) {
+	l.logFAll(_logF, format, levels.Error, args...)
+}
+
+// Log objects using format string in Fatal level and call the [FatalHandler] (Panic by default).
+func (l *MultiLogger) FatalF(format string, args ...interface{}) {
+	l.logFAll(_logF, format, levels.Fatal, args...)
+}
+
+// Log message with structured fields in Debug level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) DebugKV(msg string, keysAndValues ...any) {
+	l.logKVAll(_logKV, levels.Debug, msg, keysAndValues...)
+}
+
+// Log message with structured fields in Trace level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) TraceKV(msg string, keysAndValues ...any) {
+	l.logKVAll(_logKV, levels.Trace, msg, keysAndValues...)
+}
+
+// Log message with structured fields in Info level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) InfoKV(msg string, keysAndValues ...any) {
+	l.logKVAll(_logKV, levels.Info, msg, keysAndValues...)
+}
+
+// Log message with structured fields in Warning level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) WarningKV(msg string, keysAndValues ...any) {
+	l.logKVAll(_logKV, levels.Warning, msg, keysAndValues...)
+}
+
+// Log message with structured fields in Error level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) ErrorKV(msg string, keysAndValues ...any) {
+	l.logKVAll(_logKV, levels.Error, msg, keysAndValues...)
+}
+
+// Log message with structured fields in Fatal level and call the [FatalHandler] (Panic by default).
+// The fields are given as alternating keys and values or as [loggers.Field].
+func (l *MultiLogger) FatalKV(msg string, keysAndValues ...any) {
+	l.logKVAll(_logKV, levels.Fatal, msg, keysAndValues...)
+}
@@ -33,8 +33,25 @@ limitations under the License.
 
 package levels
 
-// LogLevel type represents the supported log levels
-type LogLevel int
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// LogLevel type represents the supported log levels.
+// It implements [encoding.TextMarshaler] and [encoding.TextUnmarshaler],
+// so it can be used in JSON and YAML configuration as name, e.g. "warning",
+// and [flag.Value], so it can be set by command line flags:
+//
+//	level := levels.Info
+//	flag.Var(&level, "log-level", "the log level")
+//
+// The underlying type is int32, so the levels of the loggers
+// can be read and changed atomically (see [sync/atomic]).
+type LogLevel int32
 
 const (
 	All     LogLevel = 0
@@ -67,3 +84,73 @@ func (level LogLevel) String() string {
 		return "Unknown"
 	}
 }
+
+var levelNames = map[string]LogLevel{
+	"all":     All,
+	"debug":   Debug,
+	"trace":   Trace,
+	"info":    Info,
+	"warning": Warning,
+	"warn":    Warning,
+	"error":   Error,
+	"fatal":   Fatal,
+}
+
+// Parses the name of the log level ignoring the case and the surrounding spaces,
+// e.g. "warning", "WARN" or "Info". The numeric values "0" to "6" are also accepted.
+func Parse(text string) (LogLevel, error) {
+	name := strings.ToLower(strings.TrimSpace(text))
+	if level, ok := levelNames[name]; ok {
+		return level, nil
+	}
+	if number, err := strconv.Atoi(name); err == nil && LogLevel(number).IsValid() {
+		return LogLevel(number), nil
+	}
+	return All, errors.Errorf("Unknown log level %q.", text).Err
+}
+
+// Reports if the level is one of the supported log levels.
+func (level LogLevel) IsValid() bool {
+	return level >= All && level <= Fatal
+}
+
+// Returns the name of the log level in lower case.
+// Returns an error for unknown levels.
+func (level LogLevel) MarshalText() ([]byte, error) {
+	if !level.IsValid() {
+		return nil, errors.Errorf("Unknown log level %d.", int(level)).Err
+	}
+	return []byte(strings.ToLower(level.String())), nil
+}
+
+// Sets the log level parsed from the text by [Parse].
+func (level *LogLevel) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*level = parsed
+	return nil
+}
+
+// Sets the log level from JSON string parsed by [Parse] or from JSON number.
+func (level *LogLevel) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		if !LogLevel(number).IsValid() {
+			return errors.Errorf("Unknown log level %d.", number).Err
+		}
+		*level = LogLevel(number)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.Errorf("Log level must be a string or a number: %w", err).Err
+	}
+	return level.UnmarshalText([]byte(text))
+}
+
+// Sets the log level parsed by [Parse]. It implements [flag.Value].
+func (level *LogLevel) Set(text string) error {
+	return level.UnmarshalText([]byte(text))
+}
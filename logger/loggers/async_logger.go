@@ -0,0 +1,339 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [OverflowPolicy] defines what [AsyncLogger] does
+// with a new message when its queue is full.
+type OverflowPolicy int
+
+const (
+	// Waits until there is space in the queue.
+	OverflowBlock OverflowPolicy = 0
+	// Drops the new message.
+	OverflowDropNewest OverflowPolicy = 1
+	// Drops the oldest message in the queue to make space for the new message.
+	OverflowDropOldest OverflowPolicy = 2
+	// Drops the new message if its level is below [AsyncOptions.DropLevel],
+	// otherwise waits until there is space in the queue.
+	OverflowDropBelowLevel OverflowPolicy = 3
+)
+
+const defaultQueueSize = 1024
+
+// Represent a set of options for [AsyncLogger].
+//   - QueueSize - the maximum number of messages waiting to be printed, 1024 is used when 0.
+//   - Overflow - what to do with a new message when the queue is full.
+//   - DropLevel - the level below which the messages are dropped with [OverflowDropBelowLevel].
+type AsyncOptions struct {
+	QueueSize int
+	Overflow  OverflowPolicy
+	DropLevel levels.LogLevel
+}
+
+// [AsyncLogger] wraps another logger and prints the messages
+// in a background goroutine, so the callers are not blocked
+// by a slow destination. The messages wait in a bounded queue
+// and [AsyncOptions.Overflow] defines what happens when it is full.
+//
+// [AsyncLogger.Stop] waits until the queued messages are printed.
+// An AsyncLogger is safe for concurrent use by multiple goroutines.
+type AsyncLogger struct {
+	logger  LoggerInterface
+	options AsyncOptions
+
+	mu      sync.RWMutex
+	running bool
+	queue   chan func()
+	done    chan struct{}
+
+	dropped atomic.Uint64
+
+	// The number of queued and handled (printed or dropped from the queue)
+	// messages, used by Flush to wait for the queued messages.
+	handledMu sync.Mutex
+	handledCh *sync.Cond
+	queued    uint64
+	handled   uint64
+}
+
+// Returns an instance of [AsyncLogger], which prints
+// the messages using the given logger in a background goroutine.
+func NewAsyncLogger(logger LoggerInterface, options AsyncOptions) *AsyncLogger {
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultQueueSize
+	}
+	asyncLogger := &AsyncLogger{logger: logger, options: options}
+	asyncLogger.handledCh = sync.NewCond(&asyncLogger.handledMu)
+	asyncLogger.Start()
+	return asyncLogger
+}
+
+// Queues the message or the object "arg" to be printed by the wrapped logger.
+// The object is formatted when it is queued, so it can be changed after the call.
+func (logger *AsyncLogger) Log(level levels.LogLevel, arg any) {
+	arg = immutableArg(arg)
+	logger.enqueue(level, func() {
+		logger.logger.Log(level, arg)
+	})
+}
+
+// Queues one or more objects "args" to be printed by the wrapped logger
+// as a message formatted using the given format string.
+// The message is formatted when it is queued, so the objects can be changed after the call.
+func (logger *AsyncLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	logger.enqueue(level, func() {
+		logger.logger.LogF(level, "%s", msg)
+	})
+}
+
+// An error with the message of another error at the time it was queued.
+type queuedError struct {
+	msg string
+	err error
+}
+
+func (err queuedError) Error() string { return err.msg }
+
+func (err queuedError) Unwrap() error { return err.err }
+
+// An object formatted as text and as JSON at the time it was queued.
+type queuedValue struct {
+	text string
+	json []byte
+}
+
+func (value queuedValue) String() string { return value.text }
+
+func (value queuedValue) MarshalJSON() ([]byte, error) { return value.json, nil }
+
+// Returns "arg" if it can not be changed after it is queued.
+// Errors are replaced by an error with their current message and
+// the other objects are replaced by their current text and JSON.
+func immutableArg(arg any) any {
+	switch value := arg.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr, float32, float64, complex64, complex128,
+		time.Time, time.Duration, messageTime:
+		return arg
+	case loggedError:
+		return loggedError{queuedError{msg: value.Error(), err: value.error}}
+	case error:
+		return queuedError{msg: value.Error(), err: value}
+	default:
+		return queuedValue{text: fmt.Sprint(arg), json: jsonValue(arg)}
+	}
+}
+
+// Returns a copy of the fields with values, which can not be changed after they are queued.
+func immutableFields(fields []Field) []Field {
+	queued := make([]Field, len(fields))
+	for i, field := range fields {
+		queued[i] = Field{Key: field.Key, Value: immutableArg(field.Value)}
+	}
+	return queued
+}
+
+// Queues the message "msg" and the fields to be printed by the wrapped logger.
+// The values of the fields are formatted when they are queued,
+// so they can be changed after the call.
+func (logger *AsyncLogger) LogKV(level levels.LogLevel, msg string, fields ...Field) {
+	fields = immutableFields(fields)
+	logger.enqueue(level, func() {
+		if fieldLogger, ok := logger.logger.(FieldLoggerInterface); ok {
+			fieldLogger.LogKV(level, msg, fields...)
+		} else if fields := PlainFields(fields); len(fields) > 0 {
+			logger.logger.Log(level, msg+" "+FormatFields(fields))
+		} else {
+			logger.logger.Log(level, msg)
+		}
+	})
+}
+
+// Queues the message "msg" and the fields to be printed by the wrapped logger
+// regardless of the log level, if it implements [OverrideLoggerInterface].
+func (logger *AsyncLogger) LogKVOverride(level levels.LogLevel, msg string, fields ...Field) {
+	overrideLogger, ok := logger.logger.(OverrideLoggerInterface)
+	if !ok {
+		logger.LogKV(level, msg, fields...)
+		return
+	}
+	fields = immutableFields(fields)
+	logger.push(level, func() {
+		overrideLogger.LogKVOverride(level, msg, fields...)
+	})
+}
+
+// Reports the log level of the wrapped logger.
+func (logger *AsyncLogger) GetLevel() levels.LogLevel {
+	return logger.logger.GetLevel()
+}
+
+// Sets the log level of the wrapped logger.
+func (logger *AsyncLogger) SetLevel(level levels.LogLevel) {
+	logger.logger.SetLevel(level)
+}
+
+// Resumes printing logs by this logger and the wrapped logger.
+func (logger *AsyncLogger) Start() {
+	logger.mu.Lock()
+	if !logger.running {
+		logger.running = true
+		logger.queue = make(chan func(), logger.options.QueueSize)
+		logger.done = make(chan struct{})
+		go logger.run(logger.queue, logger.done)
+	}
+	logger.mu.Unlock()
+	logger.logger.Start()
+}
+
+// Stops accepting new messages, waits until the queued
+// messages are printed and stops the wrapped logger.
+func (logger *AsyncLogger) Stop() {
+	logger.mu.Lock()
+	done := logger.done
+	if logger.running {
+		logger.running = false
+		close(logger.queue)
+	}
+	logger.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+	logger.logger.Stop()
+}
+
+// Reports if the logger is stopped and does not accept new messages.
+func (logger *AsyncLogger) IsStopped() bool {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	return !logger.running
+}
+
+// Waits until the messages queued before the call are printed
+// and flushes the wrapped logger if it implements [FlusherInterface].
+func (logger *AsyncLogger) Flush() error {
+	logger.handledMu.Lock()
+	target := logger.queued
+	for logger.handled < target {
+		logger.handledCh.Wait()
+	}
+	logger.handledMu.Unlock()
+	if flusher, ok := logger.logger.(FlusherInterface); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Stops this logger after printing the queued messages
+// and closes the wrapped logger if it implements [io.Closer].
+func (logger *AsyncLogger) Close() error {
+	logger.Stop()
+	if closer, ok := logger.logger.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Reports the number of messages dropped because the queue was full.
+func (logger *AsyncLogger) Dropped() uint64 {
+	return logger.dropped.Load()
+}
+
+func (logger *AsyncLogger) run(queue chan func(), done chan struct{}) {
+	defer close(done)
+	for entry := range queue {
+		entry()
+		logger.markHandled()
+	}
+}
+
+func (logger *AsyncLogger) enqueue(level levels.LogLevel, entry func()) {
+	if level < logger.logger.GetLevel() {
+		return
+	}
+	logger.push(level, entry)
+}
+
+// Queues the entry according to the overflow policy.
+func (logger *AsyncLogger) push(level levels.LogLevel, entry func()) {
+	logger.mu.RLock()
+	defer logger.mu.RUnlock()
+	if !logger.running {
+		return
+	}
+
+	logger.markQueued()
+	select {
+	case logger.queue <- entry:
+		return
+	default:
+	}
+
+	switch logger.options.Overflow {
+	case OverflowDropNewest:
+		logger.drop()
+	case OverflowDropOldest:
+		for {
+			select {
+			case logger.queue <- entry:
+				return
+			default:
+			}
+			select {
+			case <-logger.queue:
+				logger.drop()
+			default:
+			}
+		}
+	case OverflowDropBelowLevel:
+		if level < logger.options.DropLevel {
+			logger.drop()
+			return
+		}
+		logger.queue <- entry
+	default:
+		logger.queue <- entry
+	}
+}
+
+func (logger *AsyncLogger) drop() {
+	logger.dropped.Add(1)
+	logger.markHandled()
+}
+
+func (logger *AsyncLogger) markQueued() {
+	logger.handledMu.Lock()
+	logger.queued++
+	logger.handledMu.Unlock()
+}
+
+func (logger *AsyncLogger) markHandled() {
+	logger.handledMu.Lock()
+	logger.handled++
+	logger.handledCh.Broadcast()
+	logger.handledMu.Unlock()
+}
@@ -26,13 +26,29 @@ import (
 // the messages to the standard output [os.Stdout].
 type ConsoleLogger struct {
 	LoggerType
+	output *log.Logger
 }
 
+// stdoutWriter writes to the current [os.Stdout],
+// even if it has been replaced after the logger was created.
+type stdoutWriter struct{}
+
+func (stdoutWriter) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+func newConsoleOutput(flags int) *log.Logger {
+	return log.New(stdoutWriter{}, "", flags)
+}
+
+var consoleOutput = newConsoleOutput(log.LstdFlags)
+
 // Returns an instance of [ConsoleLogger] with
 // default log level "Info".
 func NewConsoleLoggerDefault() *ConsoleLogger {
 	return &ConsoleLogger{
 		LoggerType: LoggerType{Level: levels.Info},
+		output:     newConsoleOutput(log.LstdFlags),
 	}
 }
 
@@ -41,16 +57,43 @@ func NewConsoleLoggerDefault() *ConsoleLogger {
 func NewConsoleLogger(level levels.LogLevel, format string) *ConsoleLogger {
 	return &ConsoleLogger{
 		LoggerType: LoggerType{Level: level, Format: format},
+		output:     newConsoleOutput(log.LstdFlags),
 	}
 }
 
+// Returns an instance of [ConsoleLogger] with given log level,
+// which prints the messages formatted by the template.
+// Returns an error if the template is not valid (see [Template]).
+//
+//	logger, err := loggers.NewConsoleLoggerTemplate(levels.Info, "{time:15:04:05} {level} {message} {fields}")
+func NewConsoleLoggerTemplate(level levels.LogLevel, template string) (*ConsoleLogger, error) {
+	t, err := ParseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsoleLoggerFormatter(level, t), nil
+}
+
+// Returns an instance of [ConsoleLogger] with given log level,
+// which prints the messages formatted by "formatter", for example [LogfmtFormatter].
+// If "formatter" is nil, [TextFormatter] is used.
+func NewConsoleLoggerFormatter(level levels.LogLevel, formatter FormatterInterface) *ConsoleLogger {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	logger := &ConsoleLogger{
+		LoggerType: LoggerType{Level: level, formatter: formatter},
+	}
+	logger.output = newConsoleOutput(logger.outputFlags())
+	return logger
+}
+
 // Prints the message or the object "arg" into the console.
 // If there is no format set when initializing this [ConsoleLogger],
 // a default format is used: {time} {log level}: [{message}]
 func (logger *ConsoleLogger) Log(level levels.LogLevel, arg any) {
 	if logger.IsLogAllowed(level) {
-		log.SetOutput(os.Stdout)
-		logger.multi_log(level, arg)
+		logger.multi_log(logger.getOutput(), level, arg)
 	}
 }
 
@@ -59,7 +102,31 @@ func (logger *ConsoleLogger) Log(level levels.LogLevel, arg any) {
 // by the caller.
 func (logger *ConsoleLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
 	if logger.IsLogAllowed(level) {
-		log.SetOutput(os.Stdout)
-		logger.multi_logF(level, format, args...)
+		logger.multi_logF(logger.getOutput(), level, format, args...)
+	}
+}
+
+// Prints the message "msg" into the console
+// followed by the fields rendered as "key=value" pairs.
+func (logger *ConsoleLogger) LogKV(level levels.LogLevel, msg string, fields ...Field) {
+	if logger.IsLogAllowed(level) {
+		logger.multi_logKV(logger.getOutput(), level, msg, fields)
+	}
+}
+
+// Prints the message "msg" with the fields regardless
+// of the log level, unless the logger is stopped.
+func (logger *ConsoleLogger) LogKVOverride(level levels.LogLevel, msg string, fields ...Field) {
+	if !logger.IsStopped() {
+		logger.multi_logKV(logger.getOutput(), level, msg, fields)
+	}
+}
+
+// Returns the output of this logger. A [ConsoleLogger] created
+// without the constructors uses a shared output to [os.Stdout].
+func (logger *ConsoleLogger) getOutput() *log.Logger {
+	if logger.output == nil {
+		return consoleOutput
 	}
+	return logger.output
 }
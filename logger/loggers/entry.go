@@ -0,0 +1,68 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [Entry] represents a single log message with all its details,
+// which is formatted by a [Formatter].
+// The time and the error are taken from the fields created by [TimeField]
+// and [ErrorField] and the caller and the stack trace from the fields
+// with keys [CallerKey] and [StackKey].
+type Entry struct {
+	Time    time.Time
+	Level   levels.LogLevel
+	Message string
+	Caller  string
+	Stack   string
+	Fields  []Field
+	// The error logged as object, if any.
+	Error error
+}
+
+// Returns an [Entry] in the given level created now for the object "arg".
+func newArgEntry(level levels.LogLevel, arg any) Entry {
+	entry := newEntry(level, fmt.Sprint(arg), nil)
+	if err, ok := arg.(error); ok {
+		entry.Error = err
+	}
+	return entry
+}
+
+// Returns an [Entry] in the given level created now
+// or at the time of the field created by [TimeField].
+func newEntry(level levels.LogLevel, msg string, fields []Field) Entry {
+	entry := Entry{Time: time.Now(), Level: level, Message: msg}
+	if t, rest := SplitTime(fields); !t.IsZero() {
+		entry.Time, fields = t, rest
+	}
+	entry.Error, fields = SplitError(fields)
+	caller, fields := splitField(fields, CallerKey)
+	if caller != nil {
+		entry.Caller = fmt.Sprint(caller)
+	}
+	stack, fields := splitField(fields, StackKey)
+	if stack != nil {
+		entry.Stack = fmt.Sprint(stack)
+	}
+	entry.Fields = fields
+	return entry
+}
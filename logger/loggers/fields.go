@@ -0,0 +1,193 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [Field] is a structured key/value pair attached to a log message.
+// The value keeps its original type, so the loggers can decide
+// how to render it (for example as a JSON number or string).
+type Field struct {
+	Key   string
+	Value any
+}
+
+// The key of the field with the location of the log call,
+// which is added by the logger package when the caller is reported.
+// [ConsoleLogger] and [FileLogger] print it before the message,
+// while the other loggers get it as a normal field.
+const CallerKey = "caller"
+
+// The key of the field with the name of the logger,
+// which is added by the named loggers of the logger package.
+const NameKey = "logger"
+
+// The key of the field with the goroutine id, which is added
+// by the [FileLogger] with a formatter and [FileOptions.TagGoroutine].
+const GoroutineKey = "goid"
+
+// The key of the field with the stack trace of the message,
+// which is added by the logger package when stack traces are enabled.
+// [ConsoleLogger] and [FileLogger] print it on the lines after the message.
+const StackKey = "stack"
+
+// Returns a [Field] with the given key and value.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// [FieldLoggerInterface] is implemented by the loggers, which
+// support structured fields. Loggers that implement only
+// [LoggerInterface] receive the fields rendered into the message.
+type FieldLoggerInterface interface {
+	LoggerInterface
+	LogKV(level levels.LogLevel, msg string, fields ...Field)
+}
+
+// Converts alternating keys and values into fields.
+// Arguments of type [Field] are used as they are.
+// Keys, which are not strings, are converted with [fmt.Sprint]
+// and a key without value gets a nil value.
+func Fields(keysAndValues ...any) []Field {
+	fields := make([]Field, 0, len(keysAndValues)/2+1)
+	for i := 0; i < len(keysAndValues); i++ {
+		if field, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		var value any
+		if i+1 < len(keysAndValues) {
+			i++
+			value = keysAndValues[i]
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+	return fields
+}
+
+// Renders the fields as "key=value" pairs separated by spaces.
+// Values containing spaces, quotes, "=" or control characters are quoted.
+func FormatFields(fields []Field) string {
+	var sb strings.Builder
+	for i, field := range fields {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(quoteIfNeeded(field.Key))
+		sb.WriteByte('=')
+		sb.WriteString(quoteIfNeeded(fmt.Sprint(field.Value)))
+	}
+	return sb.String()
+}
+
+// Returns the value of the first field with the given key
+// and the other fields. The given slice is not modified.
+func splitField(fields []Field, key string) (any, []Field) {
+	for i, field := range fields {
+		if field.Key == key {
+			rest := make([]Field, 0, len(fields)-1)
+			rest = append(rest, fields[:i]...)
+			return field.Value, append(rest, fields[i+1:]...)
+		}
+	}
+	return nil, fields
+}
+
+// The value of the field created by [TimeField]. The type is not exported,
+// so the fields of the callers are never taken as the time of the message.
+type messageTime time.Time
+
+// Returns a field with the time of the message, which the loggers print
+// instead of the current time and do not print as a field.
+// It is added by the [log/slog] handler of the logger package
+// to keep the time of the records.
+func TimeField(t time.Time) Field {
+	return Field{Key: "time", Value: messageTime(t)}
+}
+
+// Returns the fields without the fields created by [TimeField] and [ErrorField],
+// which are rendered into the messages of the loggers without fields support.
+func PlainFields(fields []Field) []Field {
+	_, fields = SplitTime(fields)
+	_, fields = SplitError(fields)
+	return fields
+}
+
+// Returns the time of the field created by [TimeField] and the other fields.
+// Returns the zero time and the given fields, if there is no such field.
+func SplitTime(fields []Field) (time.Time, []Field) {
+	for i, field := range fields {
+		if t, ok := field.Value.(messageTime); ok {
+			rest := make([]Field, 0, len(fields)-1)
+			rest = append(rest, fields[:i]...)
+			return time.Time(t), append(rest, fields[i+1:]...)
+		}
+	}
+	return time.Time{}, fields
+}
+
+// The value of the field created by [ErrorField]. The type is not exported,
+// so the fields of the callers are never taken as the logged error.
+type loggedError struct {
+	error
+}
+
+func (err loggedError) Unwrap() error { return err.error }
+
+// Returns a field with the error logged as the object of the message,
+// which the loggers print in the same way as the errors passed to Log,
+// e.g. with key "error" in JSON. It is added by the logger package
+// when the message of an error is logged with fields.
+func ErrorField(err error) Field {
+	return Field{Key: "error", Value: loggedError{err}}
+}
+
+// Returns the error of the field created by [ErrorField] and the other fields.
+// Returns nil and the given fields, if there is no such field.
+func SplitError(fields []Field) (error, []Field) {
+	for i, field := range fields {
+		if err, ok := field.Value.(loggedError); ok {
+			rest := make([]Field, 0, len(fields)-1)
+			rest = append(rest, fields[:i]...)
+			return err.error, append(rest, fields[i+1:]...)
+		}
+	}
+	return nil, fields
+}
+
+func quoteIfNeeded(s string) string {
+	if len(s) == 0 {
+		return `""`
+	}
+	for _, r := range s {
+		if r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
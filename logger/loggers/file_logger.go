@@ -16,19 +16,59 @@ limitations under the License.
 package loggers
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/takecontrolsoft/go_multi_log/logger/levels"
 	"github.com/timandy/routine"
 )
 
-// A FileLogger is safe for concurrent use by multiple goroutines
+// A FileLogger is safe for concurrent use by multiple goroutines.
+// By default the messages of all goroutines are written into a single
+// file {prefix}_{pid}{ext}. With [FileOptions.PerGoroutine] each goroutine
+// writes into a separate file {prefix}_{pid}_{goid}{ext}.
+//
+// The files are kept open until [FileLogger.Close] is called.
 type FileLogger struct {
 	LoggerType
 	FileOptions
+
+	mu        sync.Mutex
+	files     map[string]*rotatingFile
+	recent    *list.List
+	flushStop chan struct{}
+	// The output with the goroutine id prefix last used by each goroutine.
+	tagged routine.ThreadLocal[*taggedOutput]
+}
+
+// The output of a file, which adds the goroutine id before the messages.
+type taggedOutput struct {
+	file   *rotatingFile
+	output *log.Logger
+}
+
+// [goroutineFormatter] adds the goroutine id as field [GoroutineKey]
+// to the entries of a [FileLogger] with [FileOptions.TagGoroutine],
+// so the formatted lines stay valid JSON or logfmt.
+type goroutineFormatter struct {
+	formatter FormatterInterface
+	options   *FileOptions
+}
+
+// Formats the entry with the goroutine id of the caller as the first field.
+func (formatter goroutineFormatter) Format(entry Entry) []byte {
+	if formatter.options.TagGoroutine {
+		fields := make([]Field, 0, len(entry.Fields)+1)
+		fields = append(fields, Field{Key: GoroutineKey, Value: routine.Goid()})
+		entry.Fields = append(fields, entry.Fields...)
+	}
+	return formatter.formatter.Format(entry)
 }
 
 // Represent a set of file options,
@@ -36,15 +76,62 @@ type FileLogger struct {
 //   - Directory - an absolute or relative path to log files location where the process has write access.
 //   - FilePrefix - should be a short string of symbols allowed for OS file names.
 //   - FileExtension - should starts with ".".
+//   - PerGoroutine - writes the messages of each goroutine into a separate file named with the goroutine id.
+//   - TagGoroutine - adds the goroutine id "[goid]" to each line after the time.
+//     The loggers with a formatter add it as field [GoroutineKey] instead.
+//
+// The rotation options are used to limit the size of the log files.
+// A rotated file is renamed to {name}-{time}{ext} (and compressed to {name}-{time}{ext}.gz).
+//   - MaxSize - the size in bytes after which the file is rotated, 0 means no limit.
+//   - Rollover - rotates the file at the start of every hour or day.
+//   - MaxBackups - the maximum number of rotated files to retain, 0 retains all.
+//   - MaxAge - the maximum age of rotated files to retain, 0 retains all.
+//     Both apply to all rotated files with the same FilePrefix and FileExtension in the Directory,
+//     including the files of the previous processes and of all goroutines with PerGoroutine.
+//   - Compress - compresses the rotated files with gzip.
+//   - Clock - returns the current time used for rotation, [time.Now] is used when nil.
+//
+// The buffering options are used to reduce the number of writes to the files.
+//   - BufferSize - the size in bytes of the write buffer of each file, 0 writes every line immediately.
+//     The buffer is written when it is full, every FlushInterval and on Flush, Stop and Close.
+//   - FlushInterval - how often the buffered lines are written, 1 second is used when 0.
+//   - MaxOpenFiles - the maximum number of files kept open with PerGoroutine, 64 is used when 0.
+//     The least recently used files are closed when the limit is reached.
 type FileOptions struct {
 	Directory, FilePrefix, FileExtension string
+
+	PerGoroutine bool
+	TagGoroutine bool
+
+	MaxSize    int64
+	Rollover   Rollover
+	MaxBackups int
+	MaxAge     time.Duration
+	Compress   bool
+	Clock      func() time.Time
+
+	BufferSize    int
+	FlushInterval time.Duration
+	MaxOpenFiles  int
+}
+
+const (
+	defaultFlushInterval = time.Second
+	defaultMaxOpenFiles  = 64
+)
+
+func (options *FileOptions) now() time.Time {
+	if options.Clock == nil {
+		return time.Now()
+	}
+	return options.Clock()
 }
 
 // Returns an instance of [FileLogger] with
 // default log level "Info".
 // Default [FileOptions] are used:
 //   - Directory: current executable directory.
-//   - FilePrefix:  "sync_server"
+//   - FilePrefix:  "mLog"
 //   - FileExtension:  ".log"
 func NewFileLoggerDefault() *FileLogger {
 	return &FileLogger{
@@ -65,35 +152,200 @@ func NewFileLogger(level levels.LogLevel, format string, options FileOptions) *F
 	}
 }
 
-// Prints the message or the object "arg" into files (named with goroutine id).
+// Returns an instance of [FileLogger] with given log level,
+// which writes the messages formatted by the template.
+// Returns an error if the template is not valid (see [Template]).
+func NewFileLoggerTemplate(level levels.LogLevel, template string, options FileOptions) (*FileLogger, error) {
+	t, err := ParseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileLoggerFormatter(level, t, options), nil
+}
+
+// Returns an instance of [FileLogger] with given log level,
+// which writes the messages formatted by "formatter", for example [JSONFormatter].
+// If "formatter" is nil, [TextFormatter] is used.
+func NewFileLoggerFormatter(level levels.LogLevel, formatter FormatterInterface, options FileOptions) *FileLogger {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	logger := &FileLogger{
+		LoggerType:  LoggerType{Level: level},
+		FileOptions: options,
+	}
+	logger.formatter = goroutineFormatter{formatter: formatter, options: &logger.FileOptions}
+	return logger
+}
+
+// Prints the message or the object "arg" into the log file.
 // If there is no format set when initializing this [FileLogger],
 // a default format is used: {time} {log level}: [{message}]
 func (logger *FileLogger) Log(level levels.LogLevel, arg any) {
 	if logger.IsLogAllowed(level) {
-		fLog := setFileLog(logger)
-		defer fLog.Close()
-		logger.multi_log(level, arg)
+		logger.multi_log(logger.getOutput(), level, arg)
 	}
 }
 
-// Prints one or more objects "args" into files (named with goroutine id)
+// Prints one or more objects "args" into the log file
 // as a message formatted using the given format string by the caller.
 func (logger *FileLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
 	if logger.IsLogAllowed(level) {
-		fLog := setFileLog(logger)
-		defer fLog.Close()
-		logger.multi_logF(level, format, args...)
+		logger.multi_logF(logger.getOutput(), level, format, args...)
 	}
 }
 
-func setFileLog(logger *FileLogger) *os.File {
+// Prints the message "msg" into the log file
+// followed by the fields rendered as "key=value" pairs.
+func (logger *FileLogger) LogKV(level levels.LogLevel, msg string, fields ...Field) {
+	if logger.IsLogAllowed(level) {
+		logger.multi_logKV(logger.getOutput(), level, msg, fields)
+	}
+}
+
+// Prints the message "msg" with the fields into the log file
+// regardless of the log level, unless the logger is stopped.
+func (logger *FileLogger) LogKVOverride(level levels.LogLevel, msg string, fields ...Field) {
+	if !logger.IsStopped() {
+		logger.multi_logKV(logger.getOutput(), level, msg, fields)
+	}
+}
+
+// Writes the buffered messages into the files.
+func (logger *FileLogger) Flush() error {
+	var errs []error
+	for _, f := range logger.openFiles() {
+		errs = append(errs, f.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// Writes the buffered messages and closes the files.
+// Waits until the rotated files are compressed and the old backups are removed.
+// The files are opened again if the logger is used after Close.
+func (logger *FileLogger) Close() error {
+	logger.mu.Lock()
+	if logger.flushStop != nil {
+		close(logger.flushStop)
+		logger.flushStop = nil
+	}
+	files := logger.files
+	logger.files = nil
+	logger.recent = nil
+	logger.mu.Unlock()
+
+	var errs []error
+	for _, f := range files {
+		errs = append(errs, f.detach())
+	}
+	for _, f := range files {
+		f.waitCleanup()
+	}
+	return errors.Join(errs...)
+}
+
+// Stops printing logs by this logger
+// and writes the buffered messages into the files.
+func (logger *FileLogger) Stop() {
+	logger.LoggerType.Stop()
+	logger.Flush()
+}
+
+func (logger *FileLogger) openFiles() []*rotatingFile {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	files := make([]*rotatingFile, 0, len(logger.files))
+	for _, f := range logger.files {
+		files = append(files, f)
+	}
+	return files
+}
+
+// Returns the output for the log file of the current goroutine.
+func (logger *FileLogger) getOutput() *log.Logger {
 	goid := routine.Goid()
-	fName := fmt.Sprintf("%s_%d_%d%s", logger.FilePrefix, os.Getpid(), goid, logger.FileExtension)
+	var fName string
+	if logger.PerGoroutine {
+		fName = fmt.Sprintf("%s_%d_%d%s", logger.FilePrefix, os.Getpid(), goid, logger.FileExtension)
+	} else {
+		fName = fmt.Sprintf("%s_%d%s", logger.FilePrefix, os.Getpid(), logger.FileExtension)
+	}
 	logFile := filepath.Join(logger.Directory, fName)
-	fLog, err := os.OpenFile(logFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		panic(err)
+
+	logger.mu.Lock()
+	if logger.files == nil {
+		logger.files = map[string]*rotatingFile{}
+		logger.recent = list.New()
+	}
+	f := logger.files[logFile]
+	var evicted []*rotatingFile
+	if f == nil {
+		f = newRotatingFile(logFile, &logger.FileOptions, logger.outputFlags())
+		logger.files[logFile] = f
+		f.element = logger.recent.PushFront(f)
+		evicted = logger.evictLeastRecentFiles()
+	} else {
+		logger.recent.MoveToFront(f.element)
+	}
+	if logger.BufferSize > 0 && logger.flushStop == nil {
+		logger.flushStop = make(chan struct{})
+		go logger.flushEvery(logger.flushStop)
+	}
+	if logger.TagGoroutine && logger.formatter == nil && logger.tagged == nil {
+		logger.tagged = routine.NewThreadLocal[*taggedOutput]()
+	}
+	tagged := logger.tagged
+	logger.mu.Unlock()
+
+	// The evicted files are flushed and closed without blocking the other goroutines.
+	for _, evictedFile := range evicted {
+		evictedFile.detach()
+	}
+
+	if !logger.TagGoroutine || logger.formatter != nil {
+		return f.output
+	}
+	output := tagged.Get()
+	if output == nil || output.file != f {
+		output = &taggedOutput{
+			file:   f,
+			output: log.New(f, fmt.Sprintf("[%d] ", goid), logger.outputFlags()|log.Lmsgprefix),
+		}
+		tagged.Set(output)
+	}
+	return output.output
+}
+
+// Removes the least recently used files above [FileOptions.MaxOpenFiles]
+// from the open files and returns them to be detached after the lock is released.
+func (logger *FileLogger) evictLeastRecentFiles() []*rotatingFile {
+	maxOpenFiles := logger.MaxOpenFiles
+	if maxOpenFiles <= 0 {
+		maxOpenFiles = defaultMaxOpenFiles
+	}
+	var evicted []*rotatingFile
+	for logger.recent.Len() > maxOpenFiles {
+		f := logger.recent.Remove(logger.recent.Back()).(*rotatingFile)
+		delete(logger.files, f.path)
+		evicted = append(evicted, f)
+	}
+	return evicted
+}
+
+// Writes the buffered messages every [FileOptions.FlushInterval] until "stop" is closed.
+func (logger *FileLogger) flushEvery(stop chan struct{}) {
+	interval := logger.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			logger.Flush()
+		}
 	}
-	log.SetOutput(fLog)
-	return fLog
 }
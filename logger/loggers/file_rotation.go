@@ -0,0 +1,367 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"container/list"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// [Rollover] defines how often the log files are rotated
+// regardless of their size.
+type Rollover int
+
+const (
+	// The log files are not rotated based on time.
+	RolloverNone Rollover = 0
+	// The log files are rotated at the start of every hour.
+	RolloverHourly Rollover = 1
+	// The log files are rotated at the start of every day.
+	RolloverDaily Rollover = 2
+)
+
+// Layout of the time added to the names of the rotated files.
+const backupTimeLayout = "20060102T150405.000"
+
+// Returns the start of the rollover period containing "t".
+func (rollover Rollover) periodStart(t time.Time) time.Time {
+	switch rollover {
+	case RolloverHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case RolloverDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// rotatingFile writes into a single log file
+// and rotates it based on the [FileOptions].
+// The file is kept open between the writes and the writes
+// are buffered if [FileOptions.BufferSize] is set.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	options *FileOptions
+	output  *log.Logger
+
+	file   *os.File
+	writer *bufio.Writer
+	// A detached file is not tracked by the [FileLogger] anymore,
+	// so it is opened only for the time of a single write.
+	detached bool
+
+	opened bool
+	size   int64
+	period time.Time
+
+	// The element in the list of recently used files of the [FileLogger].
+	element *list.Element
+	// Closed when the last compression and removal of backups is finished.
+	cleanup chan struct{}
+}
+
+func newRotatingFile(path string, options *FileOptions, flags int) *rotatingFile {
+	f := &rotatingFile{path: path, options: options}
+	f.output = log.New(f, "", flags)
+	return f
+}
+
+// Writes a single log line into the file.
+// The file is rotated before the write if the line does not fit
+// into [FileOptions.MaxSize] or a new rollover period has started.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.options.now()
+	if !f.opened {
+		f.opened = true
+		f.period = f.options.Rollover.periodStart(now)
+		if info, err := os.Stat(f.path); err == nil {
+			f.size = info.Size()
+		}
+	}
+	if f.shouldRotate(now, len(p)) {
+		f.rotate(now)
+	}
+
+	if f.file == nil {
+		fLog, err := os.OpenFile(f.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+		if err != nil {
+			panic(err)
+		}
+		f.file = fLog
+		if f.options.BufferSize > 0 {
+			f.writer = bufio.NewWriterSize(fLog, f.options.BufferSize)
+		}
+	}
+	var n int
+	var err error
+	if f.writer != nil {
+		n, err = f.writer.Write(p)
+	} else {
+		n, err = f.file.Write(p)
+	}
+	f.size += int64(n)
+	if f.detached {
+		f.close()
+	}
+	return n, err
+}
+
+// Writes the buffered lines into the file.
+func (f *rotatingFile) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.flush()
+}
+
+func (f *rotatingFile) flush() error {
+	if f.writer == nil {
+		return nil
+	}
+	return f.writer.Flush()
+}
+
+// Writes the buffered lines and closes the file.
+// The file is opened again on the next write.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.close()
+}
+
+// Closes the file and marks it as not tracked by the [FileLogger].
+func (f *rotatingFile) detach() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.detached = true
+	return f.close()
+}
+
+func (f *rotatingFile) close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.flush()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	f.file = nil
+	f.writer = nil
+	return err
+}
+
+func (f *rotatingFile) shouldRotate(now time.Time, n int) bool {
+	if f.options.MaxSize > 0 && f.size > 0 && f.size+int64(n) > f.options.MaxSize {
+		return true
+	}
+	return f.options.Rollover != RolloverNone &&
+		!f.options.Rollover.periodStart(now).Equal(f.period)
+}
+
+// Renames the current file to a backup file named with the rotation time.
+// The backup is compressed if required and the backups, which are not retained,
+// are removed in the background, so the writes are not blocked meanwhile.
+// If the file can not be renamed, the logging continues in the same file.
+func (f *rotatingFile) rotate(now time.Time) {
+	f.period = f.options.Rollover.periodStart(now)
+	f.close()
+	if _, err := os.Stat(f.path); err != nil {
+		f.size = 0
+		return
+	}
+	backup := f.backupName(now)
+	if err := os.Rename(f.path, backup); err != nil {
+		return
+	}
+	f.size = 0
+	if f.options.Compress || f.options.MaxBackups > 0 || f.options.MaxAge > 0 {
+		f.cleanupAsync(backup, now)
+	}
+}
+
+// Compresses the backup and removes the old backups in a new goroutine,
+// which starts after the previous cleanup of this file is finished.
+func (f *rotatingFile) cleanupAsync(backup string, now time.Time) {
+	previous := f.cleanup
+	done := make(chan struct{})
+	f.cleanup = done
+	go func() {
+		defer close(done)
+		if previous != nil {
+			<-previous
+		}
+		if f.options.Compress {
+			compressFile(backup)
+		}
+		f.removeOldBackups(now)
+	}()
+}
+
+// Waits until the compression and removal of the rotated backups are finished.
+func (f *rotatingFile) waitCleanup() {
+	f.mu.Lock()
+	cleanup := f.cleanup
+	f.mu.Unlock()
+	if cleanup != nil {
+		<-cleanup
+	}
+}
+
+// Returns a name for a backup file that does not exist yet:
+// {name}-{time}{ext} or {name}-{time}-{n}{ext}.
+func (f *rotatingFile) backupName(now time.Time) string {
+	base, ext := f.splitPath()
+	name := fmt.Sprintf("%s-%s", base, now.Local().Format(backupTimeLayout))
+	backup := name + ext
+	for i := 1; backupExists(backup); i++ {
+		backup = fmt.Sprintf("%s-%d%s", name, i, ext)
+	}
+	return backup
+}
+
+func backupExists(backup string) bool {
+	for _, name := range []string{backup, backup + ".gz"} {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *rotatingFile) splitPath() (string, string) {
+	ext := filepath.Ext(f.path)
+	return strings.TrimSuffix(f.path, ext), ext
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+// Removes the backups exceeding [FileOptions.MaxBackups]
+// or older than [FileOptions.MaxAge].
+func (f *rotatingFile) removeOldBackups(now time.Time) {
+	if f.options.MaxBackups <= 0 && f.options.MaxAge <= 0 {
+		return
+	}
+	backups := f.listBackups()
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].time.Equal(backups[j].time) {
+			return backups[i].path > backups[j].path
+		}
+		return backups[i].time.After(backups[j].time)
+	})
+	for i, backup := range backups {
+		tooMany := f.options.MaxBackups > 0 && i >= f.options.MaxBackups
+		tooOld := f.options.MaxAge > 0 && now.Sub(backup.time) > f.options.MaxAge
+		if tooMany || tooOld {
+			os.Remove(backup.path)
+		}
+	}
+}
+
+// Returns the backups with the time parsed from their names.
+// The backups of all files with the same prefix are returned,
+// {prefix}_{pid}-{time}{ext} and {prefix}_{pid}_{goid}-{time}{ext},
+// so the backups of the previous processes are removed as well.
+func (f *rotatingFile) listBackups() []backupFile {
+	_, ext := f.splitPath()
+	prefix := f.options.FilePrefix + "_"
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil
+	}
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ids, stamp, found := strings.Cut(strings.TrimPrefix(name, prefix), "-")
+		if !found || !isFileIds(ids) {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) || len(stamp) < len(backupTimeLayout)+len(ext) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeLayout, stamp[:len(backupTimeLayout)], time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(filepath.Dir(f.path), name), time: t})
+	}
+	return backups
+}
+
+// Reports if "ids" is the process id and optionally the goroutine id
+// of a log file name, e.g. "1234" or "1234_56".
+func isFileIds(ids string) bool {
+	pid, goid, perGoroutine := strings.Cut(ids, "_")
+	return isDigits(pid) && (!perGoroutine || isDigits(goid))
+}
+
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Compresses the file with gzip into "{name}.gz" and removes the original.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(name)
+}
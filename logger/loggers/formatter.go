@@ -0,0 +1,129 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// [FormatterInterface] formats a log [Entry] as a single line ending with new line.
+// A formatter can be combined with any destination using [WriterLogger]
+// or [NewFileLoggerFormatter].
+//
+// The package provides [TextFormatter], [JSONFormatter] and [Template].
+type FormatterInterface interface {
+	Format(entry Entry) []byte
+}
+
+// [TextFormatter] formats the entries in the default format
+// of [ConsoleLogger] and [FileLogger]:
+//
+//	2024/01/14 10:00:00 main.go:12: INFO: [message] key=value
+//
+// The stack trace is printed on the lines after the message.
+type TextFormatter struct{}
+
+// Formats the entry as text line.
+func (TextFormatter) Format(entry Entry) []byte {
+	var sb strings.Builder
+	sb.WriteString(entry.Time.Format(DefaultTimeLayout))
+	sb.WriteByte(' ')
+	if len(entry.Caller) > 0 {
+		sb.WriteString(entry.Caller)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(strings.ToUpper(entry.Level.String()))
+	sb.WriteString(": [")
+	sb.WriteString(entry.Message)
+	sb.WriteByte(']')
+	if len(entry.Fields) > 0 {
+		sb.WriteByte(' ')
+		sb.WriteString(FormatFields(entry.Fields))
+	}
+	if len(entry.Stack) > 0 {
+		sb.WriteByte('\n')
+		sb.WriteString(indentLines(entry.Stack))
+	}
+	sb.WriteByte('\n')
+	return []byte(sb.String())
+}
+
+// [JSONFormatter] formats the entries as single line JSON objects with keys
+// "time", "level", "msg", "caller", "error", the fields and "stack":
+//
+//	{"time":"2024-01-14T10:00:00.123+02:00","level":"Info","msg":"message","user":42}
+//
+// The keys "caller", "error" and "stack" are added only when they have values.
+type JSONFormatter struct{}
+
+// Formats the entry as JSON object.
+func (JSONFormatter) Format(entry Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONPair(&buf, "time", entry.Time.Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	writeJSONPair(&buf, "level", entry.Level.String())
+	buf.WriteByte(',')
+	writeJSONPair(&buf, "msg", entry.Message)
+	if len(entry.Caller) > 0 {
+		buf.WriteByte(',')
+		writeJSONPair(&buf, CallerKey, entry.Caller)
+	}
+	if entry.Error != nil {
+		buf.WriteByte(',')
+		writeJSONPair(&buf, "error", entry.Error)
+	}
+	for _, field := range entry.Fields {
+		buf.WriteByte(',')
+		writeJSONPair(&buf, field.Key, field.Value)
+	}
+	if len(entry.Stack) > 0 {
+		buf.WriteByte(',')
+		writeJSONPair(&buf, StackKey, entry.Stack)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func writeJSONPair(buf *bytes.Buffer, key string, value any) {
+	keyBytes, _ := json.Marshal(key)
+	buf.Write(keyBytes)
+	buf.WriteByte(':')
+	buf.Write(jsonValue(value))
+}
+
+// Encodes the value as JSON. Errors are encoded with their message
+// and values, which can not be encoded, are encoded as strings.
+func jsonValue(value any) []byte {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		valueBytes, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return valueBytes
+}
+
+var (
+	_ FormatterInterface = TextFormatter{}
+	_ FormatterInterface = JSONFormatter{}
+	_ FormatterInterface = (*Template)(nil)
+)
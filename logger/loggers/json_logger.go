@@ -0,0 +1,56 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"io"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [JSONLogger] type represents the logger that prints every message
+// as a single line JSON object into an [io.Writer]:
+//
+//	{"time":"2024-01-14T10:00:00.123+02:00","level":"Info","msg":"message","user":42}
+//
+// It is a [WriterLogger] with [JSONFormatter].
+// Errors logged as objects are added with key "error".
+// A JSONLogger is safe for concurrent use by multiple goroutines.
+type JSONLogger struct {
+	WriterLogger
+}
+
+// Returns an instance of [JSONLogger] with
+// default log level "Info", which prints to [os.Stdout].
+func NewJSONLoggerDefault() *JSONLogger {
+	return NewJSONLogger(levels.Info, nil)
+}
+
+// Returns an instance of [JSONLogger] with given log level,
+// which prints to the writer "w" (for example an [os.File]).
+// If "w" is nil, the messages are printed to [os.Stdout].
+func NewJSONLogger(level levels.LogLevel, w io.Writer) *JSONLogger {
+	if w == nil {
+		w = stdoutWriter{}
+	}
+	return &JSONLogger{
+		WriterLogger: WriterLogger{
+			LoggerType: LoggerType{Level: level},
+			writer:     w,
+			formatter:  JSONFormatter{},
+		},
+	}
+}
@@ -0,0 +1,117 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [LogfmtFormatter] formats the entries as logfmt lines with keys
+// "ts", "level", "msg", "caller", "error", the fields and "stack":
+//
+//	ts=2024-01-14T10:00:00.123+02:00 level=info msg="Request done" user=42
+//
+// Values containing spaces, quotes, "=" or control characters are quoted
+// and escaped like Go strings, e.g. new lines as "\n". Characters, which are
+// not allowed in keys, are replaced with "_".
+// The keys "caller", "error" and "stack" are added only when they have values.
+type LogfmtFormatter struct{}
+
+// Formats the entry as logfmt line.
+func (LogfmtFormatter) Format(entry Entry) []byte {
+	var sb strings.Builder
+	writeLogfmtPair(&sb, "ts", entry.Time.Format(time.RFC3339Nano))
+	writeLogfmtPair(&sb, "level", strings.ToLower(entry.Level.String()))
+	writeLogfmtPair(&sb, "msg", entry.Message)
+	if len(entry.Caller) > 0 {
+		writeLogfmtPair(&sb, CallerKey, entry.Caller)
+	}
+	if entry.Error != nil {
+		writeLogfmtPair(&sb, "error", entry.Error.Error())
+	}
+	for _, field := range entry.Fields {
+		writeLogfmtPair(&sb, field.Key, fmt.Sprint(field.Value))
+	}
+	if len(entry.Stack) > 0 {
+		writeLogfmtPair(&sb, StackKey, entry.Stack)
+	}
+	sb.WriteByte('\n')
+	return []byte(sb.String())
+}
+
+func writeLogfmtPair(sb *strings.Builder, key, value string) {
+	if sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(logfmtKey(key))
+	sb.WriteByte('=')
+	sb.WriteString(quoteIfNeeded(value))
+}
+
+// Returns the key with the characters, which are not allowed
+// in logfmt keys, replaced with "_".
+func logfmtKey(key string) string {
+	if len(key) == 0 {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// [LogfmtLogger] type represents the logger that prints every message
+// as a logfmt line into an [io.Writer]:
+//
+//	ts=2024-01-14T10:00:00.123+02:00 level=info msg="Request done" user=42
+//
+// It is a [WriterLogger] with [LogfmtFormatter].
+// A LogfmtLogger is safe for concurrent use by multiple goroutines.
+type LogfmtLogger struct {
+	WriterLogger
+}
+
+// Returns an instance of [LogfmtLogger] with
+// default log level "Info", which prints to [os.Stdout].
+func NewLogfmtLoggerDefault() *LogfmtLogger {
+	return NewLogfmtLogger(levels.Info, nil)
+}
+
+// Returns an instance of [LogfmtLogger] with given log level,
+// which prints to the writer "w" (for example an [os.File]).
+// If "w" is nil, the messages are printed to [os.Stdout].
+func NewLogfmtLogger(level levels.LogLevel, w io.Writer) *LogfmtLogger {
+	if w == nil {
+		w = stdoutWriter{}
+	}
+	return &LogfmtLogger{
+		WriterLogger: WriterLogger{
+			LoggerType: LoggerType{Level: level},
+			writer:     w,
+			formatter:  LogfmtFormatter{},
+		},
+	}
+}
+
+var _ FormatterInterface = LogfmtFormatter{}
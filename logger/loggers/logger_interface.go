@@ -24,11 +24,16 @@ limitations under the License.
 //
 // The package supports:
 //   - [loggers.ConsoleLogger], which logs the messages to the console
-//   - [loggers.FileLogger], which logs the messages to files separated by goroutines.
+//   - [loggers.FileLogger], which logs the messages to a single file or to files separated by goroutines.
+//   - [loggers.JSONLogger], which logs the messages as JSON objects to any [io.Writer].
+//   - [loggers.LogfmtLogger], which logs the messages as logfmt lines to any [io.Writer].
+//   - [loggers.WriterLogger], which logs the messages formatted by any [loggers.FormatterInterface] to any [io.Writer].
+//   - [loggers.SlogLogger], which passes the messages to any [log/slog.Handler].
+//   - [loggers.AsyncLogger], which prints the messages of another logger in a background goroutine.
 //
 // The common interface [loggers.LoggerInterface]
 // makes it possible this package to be extended by implementing
-// additional custom loggers for logging in json, xml and other formats,
+// additional custom loggers for logging in xml and other formats,
 // as well as sending the logs to external services.
 //
 // # Take Control - software & infrastructure
@@ -42,6 +47,8 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/takecontrolsoft/go_multi_log/logger/levels"
 )
@@ -58,15 +65,35 @@ type LoggerInterface interface {
 	Stop()
 }
 
+// [OverrideLoggerInterface] is implemented by the loggers, which print
+// the messages of named loggers with overridden level (see logger.SetNamedLevel)
+// regardless of their own log level, if it is Info or below. Stopped loggers print no messages.
+// Other loggers print the messages only if their own level allows it.
+type OverrideLoggerInterface interface {
+	LogKVOverride(level levels.LogLevel, msg string, fields ...Field)
+}
+
+// [FlusherInterface] is implemented by the loggers, which buffer
+// the messages before printing them.
+type FlusherInterface interface {
+	Flush() error
+}
+
 // [LoggerType] provides base implementation of [loggers.LoggerInterface]
 // and can be reused when extending the package with adding new
 // loggers implementations.
+// The log level and the stopped state can be changed
+// while other goroutines are logging. Use [loggers.LoggerType.GetLevel]
+// and [loggers.LoggerType.SetLevel] instead of the field Level
+// after the logger is registered.
 type LoggerType struct {
 	LoggerInterface
 	Level  levels.LogLevel
 	Format string
 
-	isStopped bool
+	// 1 if the logger is stopped, accessed atomically.
+	isStopped int32
+	formatter FormatterInterface
 }
 
 // Reports if the log message will be printed based on the
@@ -75,39 +102,143 @@ type LoggerType struct {
 // [loggers.LoggerType.IsLogAllowed] returns false if the logger
 // is stopped using [loggers.LoggerType.Stop] function.
 func (logger *LoggerType) IsLogAllowed(level levels.LogLevel) bool {
-	return !logger.isStopped && level >= logger.Level
+	return !logger.IsStopped() && level >= logger.GetLevel()
 }
 
 // Reports the log level for this logger.
 func (logger *LoggerType) GetLevel() levels.LogLevel {
-	return logger.Level
+	return levels.LogLevel(atomic.LoadInt32((*int32)(&logger.Level)))
 }
 
 // Sets the log level for this logger.
 func (logger *LoggerType) SetLevel(level levels.LogLevel) {
-	logger.Level = level
+	atomic.StoreInt32((*int32)(&logger.Level), int32(level))
 }
 
 // Resumes printing logs by this logger.
 func (logger *LoggerType) Start() {
-	logger.isStopped = false
+	atomic.StoreInt32(&logger.isStopped, 0)
 }
 
 // Stops printing logs by this logger.
 func (logger *LoggerType) Stop() {
-	logger.isStopped = true
+	atomic.StoreInt32(&logger.isStopped, 1)
 }
 
-func (logger *LoggerType) multi_log(level levels.LogLevel, arg any) {
-	var f string
-	if len(logger.Format) > 0 {
-		f = logger.Format
-	} else {
-		f = fmt.Sprintf("%s: [%s]", strings.ToUpper(level.String()), "%v")
+// Reports if the logger is stopped using [loggers.LoggerType.Stop].
+func (logger *LoggerType) IsStopped() bool {
+	return atomic.LoadInt32(&logger.isStopped) == 1
+}
+
+func (logger *LoggerType) multi_log(out *log.Logger, level levels.LogLevel, arg any) {
+	if logger.formatter != nil {
+		logger.multi_logEntry(out, newArgEntry(level, arg))
+		return
+	}
+	logger.multi_logF(out, level, logger.messageFormat(level), arg)
+}
+
+// Prints the message followed by the fields rendered as "key=value" pairs.
+// The caller field is printed before the message as "file.go:123: "
+// and the stack trace field is printed on the following lines.
+func (logger *LoggerType) multi_logKV(out *log.Logger, level levels.LogLevel, msg string, fields []Field) {
+	if logger.formatter != nil {
+		logger.multi_logEntry(out, newEntry(level, msg, fields))
+		return
+	}
+	format := logger.messageFormat(level)
+	args := []interface{}{msg}
+	at, fields := SplitTime(fields)
+	// The error is printed as the message like by Log.
+	_, fields = SplitError(fields)
+	caller, fields := splitField(fields, CallerKey)
+	if caller != nil {
+		format = "%v: " + format
+		args = []interface{}{caller, msg}
+	}
+	stack, fields := splitField(fields, StackKey)
+	if len(fields) > 0 {
+		format += " %s"
+		args = append(args, FormatFields(fields))
+	}
+	if stack != nil {
+		format += "\n%s"
+		args = append(args, indentLines(fmt.Sprint(stack)))
+	}
+	if !at.IsZero() {
+		printAt(out, at, fmt.Sprintf(format, args...))
+		return
 	}
-	logger.multi_logF(level, f, arg)
+	logger.multi_logF(out, level, format, args...)
 }
 
-func (logger *LoggerType) multi_logF(level levels.LogLevel, format string, args ...interface{}) {
-	log.Printf(format, args...)
+// Prints the line into the output with the time "t" instead of the current time.
+// The time is formatted according to the date and time flags of the output.
+func printAt(out *log.Logger, t time.Time, line string) {
+	flags := out.Flags()
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) == 0 {
+		out.Print(line)
+		return
+	}
+	var sb strings.Builder
+	if flags&log.Lmsgprefix == 0 {
+		sb.WriteString(out.Prefix())
+	}
+	if flags&log.LUTC != 0 {
+		t = t.UTC()
+	}
+	if flags&log.Ldate != 0 {
+		sb.WriteString(t.Format("2006/01/02 "))
+	}
+	if flags&log.Lmicroseconds != 0 {
+		sb.WriteString(t.Format("15:04:05.000000 "))
+	} else if flags&log.Ltime != 0 {
+		sb.WriteString(t.Format("15:04:05 "))
+	}
+	if flags&log.Lmsgprefix != 0 {
+		sb.WriteString(out.Prefix())
+	}
+	sb.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteByte('\n')
+	}
+	out.Writer().Write([]byte(sb.String()))
+}
+
+// Prints the formatted message into the given output.
+// Each logger passes its own output, so the standard "log"
+// package output is never changed.
+func (logger *LoggerType) multi_logF(out *log.Logger, level levels.LogLevel, format string, args ...interface{}) {
+	if logger.formatter != nil {
+		logger.multi_logEntry(out, newEntry(level, fmt.Sprintf(format, args...), nil))
+		return
+	}
+	out.Printf(format, args...)
+}
+
+// Prints the entry formatted by the formatter of this logger.
+func (logger *LoggerType) multi_logEntry(out *log.Logger, entry Entry) {
+	out.Print(string(logger.formatter.Format(entry)))
+}
+
+// Returns the flags of the outputs of this logger. The time is
+// not added by the outputs, if the logger has a formatter.
+func (logger *LoggerType) outputFlags() int {
+	if logger.formatter != nil {
+		return 0
+	}
+	return log.LstdFlags
+}
+
+// Indents each line with a tab.
+func indentLines(s string) string {
+	return "\t" + strings.ReplaceAll(s, "\n", "\n\t")
+}
+
+// Returns the format string for a single message.
+func (logger *LoggerType) messageFormat(level levels.LogLevel) string {
+	if len(logger.Format) > 0 {
+		return logger.Format
+	}
+	return fmt.Sprintf("%s: [%s]", strings.ToUpper(level.String()), "%v")
 }
@@ -0,0 +1,152 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [ColorMode] defines if [PrettyFormatter] uses ANSI colors.
+type ColorMode int
+
+const (
+	// Colors are used if [os.Stdout] is a terminal
+	// and the environment variable NO_COLOR is not set.
+	ColorAuto ColorMode = iota
+	// Colors are always used.
+	ColorAlways
+	// Colors are never used.
+	ColorNever
+)
+
+// The width of the message column, when the message is followed by fields.
+const prettyMessageWidth = 40
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiGray  = "\x1b[90m"
+)
+
+var levelColors = map[levels.LogLevel]string{
+	levels.Debug:   "\x1b[90m",
+	levels.Trace:   "\x1b[36m",
+	levels.Info:    "\x1b[32m",
+	levels.Warning: "\x1b[33m",
+	levels.Error:   "\x1b[31m",
+	levels.Fatal:   "\x1b[1;35m",
+}
+
+var levelNames = map[levels.LogLevel]string{
+	levels.Debug:   "DEBUG",
+	levels.Trace:   "TRACE",
+	levels.Info:    "INFO",
+	levels.Warning: "WARN",
+	levels.Error:   "ERROR",
+	levels.Fatal:   "FATAL",
+}
+
+// [PrettyFormatter] formats the entries as human-friendly lines
+// for local development with short time and aligned columns:
+//
+//	10:05:30.123 WARN  main.go:12 Disk is almost full                      free="1 GB"
+//
+// With Color the level and the field keys are printed in ANSI colors
+// per level and the caller in gray.
+type PrettyFormatter struct {
+	Color bool
+}
+
+// Formats the entry as human-friendly line.
+func (formatter PrettyFormatter) Format(entry Entry) []byte {
+	var sb strings.Builder
+	color := levelColors[entry.Level]
+	sb.WriteString(entry.Time.Format("15:04:05.000"))
+	sb.WriteByte(' ')
+	name, ok := levelNames[entry.Level]
+	if !ok {
+		name = strings.ToUpper(entry.Level.String())
+	}
+	formatter.writeColored(&sb, color, fmt.Sprintf("%-5s", name))
+	if len(entry.Caller) > 0 {
+		sb.WriteByte(' ')
+		formatter.writeColored(&sb, ansiGray, entry.Caller)
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(entry.Message)
+	fields := entry.Fields
+	if entry.Error != nil && entry.Error.Error() != entry.Message {
+		fields = append([]Field{{Key: "error", Value: entry.Error}}, fields...)
+	}
+	if len(fields) > 0 {
+		if padding := prettyMessageWidth - len(entry.Message); padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		for _, field := range fields {
+			sb.WriteByte(' ')
+			formatter.writeColored(&sb, color, quoteIfNeeded(field.Key)+"=")
+			sb.WriteString(quoteIfNeeded(fmt.Sprint(field.Value)))
+		}
+	}
+	if len(entry.Stack) > 0 {
+		sb.WriteByte('\n')
+		formatter.writeColored(&sb, ansiGray, indentLines(entry.Stack))
+	}
+	sb.WriteByte('\n')
+	return []byte(sb.String())
+}
+
+func (formatter PrettyFormatter) writeColored(sb *strings.Builder, color, text string) {
+	if formatter.Color && len(color) > 0 {
+		sb.WriteString(color)
+		sb.WriteString(text)
+		sb.WriteString(ansiReset)
+		return
+	}
+	sb.WriteString(text)
+}
+
+// Reports if the colors are used in the given mode.
+// With [ColorAuto] the colors are used only if [os.Stdout] is a terminal
+// and the environment variable NO_COLOR is empty.
+func (mode ColorMode) enabled() bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if len(os.Getenv("NO_COLOR")) > 0 {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Returns an instance of [ConsoleLogger] with given log level,
+// which prints the messages formatted by [PrettyFormatter].
+// The color mode is resolved when the logger is created,
+// so [ColorAuto] never prints colors into pipes, files or CI logs.
+//
+//	logger.RegisterLogger("pretty", loggers.NewPrettyConsoleLogger(levels.Debug, loggers.ColorAuto))
+func NewPrettyConsoleLogger(level levels.LogLevel, color ColorMode) *ConsoleLogger {
+	return NewConsoleLoggerFormatter(level, PrettyFormatter{Color: color.enabled()})
+}
+
+var _ FormatterInterface = PrettyFormatter{}
@@ -0,0 +1,143 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// Levels of [slog.Level] used for the log levels,
+// which do not exist in package "log/slog".
+const (
+	SlogLevelTrace slog.Level = slog.LevelDebug + 2
+	SlogLevelFatal slog.Level = slog.LevelError + 4
+)
+
+// Converts [levels.LogLevel] to [slog.Level].
+func SlogLevel(level levels.LogLevel) slog.Level {
+	switch {
+	case level <= levels.All:
+		return slog.LevelDebug - 4
+	case level == levels.Debug:
+		return slog.LevelDebug
+	case level == levels.Trace:
+		return SlogLevelTrace
+	case level == levels.Info:
+		return slog.LevelInfo
+	case level == levels.Warning:
+		return slog.LevelWarn
+	case level == levels.Error:
+		return slog.LevelError
+	default:
+		return SlogLevelFatal
+	}
+}
+
+// Converts [slog.Level] to [levels.LogLevel].
+// The levels between two known levels are converted to the lower one.
+func LevelFromSlog(level slog.Level) levels.LogLevel {
+	switch {
+	case level < SlogLevelTrace:
+		return levels.Debug
+	case level < slog.LevelInfo:
+		return levels.Trace
+	case level < slog.LevelWarn:
+		return levels.Info
+	case level < slog.LevelError:
+		return levels.Warning
+	case level < SlogLevelFatal:
+		return levels.Error
+	default:
+		return levels.Fatal
+	}
+}
+
+// [SlogLogger] type represents the logger that passes
+// the messages as records to a [slog.Handler].
+type SlogLogger struct {
+	LoggerType
+	handler slog.Handler
+}
+
+// Returns an instance of [SlogLogger] with given log level,
+// which writes into the given [slog.Handler].
+func NewSlogLogger(level levels.LogLevel, handler slog.Handler) *SlogLogger {
+	return &SlogLogger{
+		LoggerType: LoggerType{Level: level},
+		handler:    handler,
+	}
+}
+
+// Passes the message or the object "arg" to the handler.
+// If "arg" is an error, it is added as attribute with key "error".
+func (logger *SlogLogger) Log(level levels.LogLevel, arg any) {
+	if logger.IsLogAllowed(level) {
+		var fields []Field
+		if err, ok := arg.(error); ok {
+			fields = []Field{{Key: "error", Value: err}}
+		}
+		logger.handle(level, fmt.Sprint(arg), fields)
+	}
+}
+
+// Passes one or more objects "args" formatted using
+// the given format string to the handler.
+func (logger *SlogLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
+	if logger.IsLogAllowed(level) {
+		logger.handle(level, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// Passes the message "msg" to the handler with the fields as attributes.
+func (logger *SlogLogger) LogKV(level levels.LogLevel, msg string, fields ...Field) {
+	if logger.IsLogAllowed(level) {
+		logger.handle(level, msg, fields)
+	}
+}
+
+// Passes the message "msg" to the handler regardless
+// of the log level, unless the logger is stopped.
+// The handler can still drop the message.
+func (logger *SlogLogger) LogKVOverride(level levels.LogLevel, msg string, fields ...Field) {
+	if !logger.IsStopped() {
+		logger.handle(level, msg, fields)
+	}
+}
+
+func (logger *SlogLogger) handle(level levels.LogLevel, msg string, fields []Field) {
+	ctx := context.Background()
+	slogLevel := SlogLevel(level)
+	if !logger.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+	at, fields := SplitTime(fields)
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if err, rest := SplitError(fields); err != nil {
+		fields = append(rest, Field{Key: "error", Value: err})
+	}
+	record := slog.NewRecord(at, slogLevel, msg, 0)
+	for _, field := range fields {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+	logger.handler.Handle(ctx, record)
+}
@@ -0,0 +1,171 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// The layout of the time used by the placeholder "{time}" without layout.
+// It is the same as the time printed by the standard "log" package.
+const DefaultTimeLayout = "2006/01/02 15:04:05"
+
+// [Template] formats the log messages as lines with named placeholders:
+//   - {time} or {time:layout} - the time of the message formatted
+//     with [DefaultTimeLayout] or the given [time.Layout] layout.
+//   - {level} - the log level in upper case, e.g. "INFO".
+//   - {caller} - the location of the log call, e.g. "main.go:12".
+//   - {message} - the message.
+//   - {fields} - the fields rendered as "key=value" pairs.
+//   - {stack} - the stack trace.
+//
+// "{{" and "}}" are printed as "{" and "}". A placeholder with empty value
+// removes the single space after it, so "{caller} {message}" is printed
+// as "{message}" when the caller is not reported. The stack trace is printed
+// on the lines after the message if the template has no "{stack}" placeholder.
+//
+//	template, err := loggers.ParseTemplate("{time:15:04:05.000} {level} {caller} {message} {fields}")
+type Template struct {
+	text     string
+	segments []templateSegment
+	hasStack bool
+}
+
+type placeholder int
+
+const (
+	literalText placeholder = iota
+	timePlaceholder
+	levelPlaceholder
+	callerPlaceholder
+	messagePlaceholder
+	fieldsPlaceholder
+	stackPlaceholder
+)
+
+var placeholders = map[string]placeholder{
+	"time":    timePlaceholder,
+	"level":   levelPlaceholder,
+	"caller":  callerPlaceholder,
+	"message": messagePlaceholder,
+	"fields":  fieldsPlaceholder,
+	"stack":   stackPlaceholder,
+}
+
+type templateSegment struct {
+	kind placeholder
+	// The text of a literal segment or the layout of the time.
+	text string
+}
+
+// Parses the template. Returns an error for unknown placeholders,
+// not closed or not opened braces and options of placeholders,
+// which do not support them.
+func ParseTemplate(text string) (*Template, error) {
+	t := &Template{text: text}
+	var literal strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "{{"), strings.HasPrefix(text[i:], "}}"):
+			literal.WriteByte(text[i])
+			i++
+		case text[i] == '}':
+			return nil, errors.Errorf("Template %q has not opened \"}\" at position %d.", text, i).Err
+		case text[i] == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, errors.Errorf("Template %q has not closed \"{\" at position %d.", text, i).Err
+			}
+			segment, err := parsePlaceholder(text[i+1 : i+end])
+			if err != nil {
+				return nil, errors.Errorf("Template %q is not valid: %w", text, err).Err
+			}
+			if literal.Len() > 0 {
+				t.segments = append(t.segments, templateSegment{kind: literalText, text: literal.String()})
+				literal.Reset()
+			}
+			t.segments = append(t.segments, segment)
+			t.hasStack = t.hasStack || segment.kind == stackPlaceholder
+			i += end
+		default:
+			literal.WriteByte(text[i])
+		}
+	}
+	if literal.Len() > 0 {
+		t.segments = append(t.segments, templateSegment{kind: literalText, text: literal.String()})
+	}
+	return t, nil
+}
+
+func parsePlaceholder(text string) (templateSegment, error) {
+	name, option, hasOption := strings.Cut(text, ":")
+	kind, ok := placeholders[name]
+	if !ok {
+		return templateSegment{}, errors.Errorf("unknown placeholder {%s}", text).Err
+	}
+	if kind != timePlaceholder && hasOption {
+		return templateSegment{}, errors.Errorf("placeholder {%s} does not support options", name).Err
+	}
+	if kind == timePlaceholder {
+		if !hasOption {
+			option = DefaultTimeLayout
+		} else if len(option) == 0 {
+			return templateSegment{}, errors.Errorf("placeholder {%s} has empty time layout", text).Err
+		}
+	}
+	return templateSegment{kind: kind, text: option}, nil
+}
+
+// Returns the template text.
+func (t *Template) String() string {
+	return t.text
+}
+
+// Formats the entry as a single line ending with new line.
+func (t *Template) Format(entry Entry) []byte {
+	var sb strings.Builder
+	skipSpace := false
+	for _, segment := range t.segments {
+		value := segment.text
+		switch segment.kind {
+		case literalText:
+			if skipSpace {
+				value = strings.TrimPrefix(value, " ")
+			}
+		case timePlaceholder:
+			value = entry.Time.Format(segment.text)
+		case levelPlaceholder:
+			value = strings.ToUpper(entry.Level.String())
+		case callerPlaceholder:
+			value = entry.Caller
+		case messagePlaceholder:
+			value = entry.Message
+		case fieldsPlaceholder:
+			value = FormatFields(entry.Fields)
+		case stackPlaceholder:
+			value = entry.Stack
+		}
+		skipSpace = segment.kind != literalText && len(value) == 0
+		sb.WriteString(value)
+	}
+	line := strings.TrimRight(sb.String(), " ")
+	if !t.hasStack && len(entry.Stack) > 0 {
+		line += "\n" + indentLines(entry.Stack)
+	}
+	return []byte(line + "\n")
+}
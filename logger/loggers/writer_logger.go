@@ -0,0 +1,93 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+)
+
+// [WriterLogger] type represents the logger that writes the messages
+// formatted by a [FormatterInterface] into an [io.Writer],
+// for example a network connection or a buffer.
+// Each message is written with a single call to Write.
+// A WriterLogger is safe for concurrent use by multiple goroutines.
+type WriterLogger struct {
+	LoggerType
+	mu        sync.Mutex
+	writer    io.Writer
+	formatter FormatterInterface
+}
+
+// Returns an instance of [WriterLogger] with given log level,
+// which writes the messages formatted by "formatter" into the writer "w".
+// If "w" is nil, the messages are printed to [os.Stdout].
+// If "formatter" is nil, [TextFormatter] is used.
+//
+//	logger := loggers.NewWriterLogger(levels.Info, conn, loggers.JSONFormatter{})
+func NewWriterLogger(level levels.LogLevel, w io.Writer, formatter FormatterInterface) *WriterLogger {
+	if w == nil {
+		w = stdoutWriter{}
+	}
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	return &WriterLogger{
+		LoggerType: LoggerType{Level: level},
+		writer:     w,
+		formatter:  formatter,
+	}
+}
+
+// Writes the message or the object "arg".
+// If "arg" is an error, it is passed to the formatter as [Entry.Error].
+func (logger *WriterLogger) Log(level levels.LogLevel, arg any) {
+	if logger.IsLogAllowed(level) {
+		logger.write(newArgEntry(level, arg))
+	}
+}
+
+// Writes one or more objects "args" formatted using the given format string.
+func (logger *WriterLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
+	if logger.IsLogAllowed(level) {
+		logger.write(newEntry(level, fmt.Sprintf(format, args...), nil))
+	}
+}
+
+// Writes the message "msg" with the fields.
+func (logger *WriterLogger) LogKV(level levels.LogLevel, msg string, fields ...Field) {
+	if logger.IsLogAllowed(level) {
+		logger.write(newEntry(level, msg, fields))
+	}
+}
+
+// Writes the message "msg" with the fields regardless
+// of the log level, unless the logger is stopped.
+func (logger *WriterLogger) LogKVOverride(level levels.LogLevel, msg string, fields ...Field) {
+	if !logger.IsStopped() {
+		logger.write(newEntry(level, msg, fields))
+	}
+}
+
+func (logger *WriterLogger) write(entry Entry) {
+	line := logger.formatter.Format(entry)
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.writer.Write(line)
+}
@@ -27,8 +27,12 @@ limitations under the License.
 //
 // More than one loggers could be registered at the same time.
 //
-// This package provides implementations of [loggers.ConsoleLogger]
-// and [loggers.FileLogger].
+// The package level functions log in the loggers registered in a default
+// [MultiLogger]. Additional instances with their own registry of loggers
+// can be created with [New].
+//
+// This package provides implementations of [loggers.ConsoleLogger],
+// [loggers.FileLogger] and [loggers.JSONLogger].
 //
 // Custom loggers could be also implemented using the [loggers.LoggerInterface].
 //
@@ -42,153 +46,151 @@ package logger
 import (
 	"sync"
 
-	"github.com/go-errors/errors"
-	"github.com/takecontrolsoft/go_multi_log/logger/levels"
 	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
 )
 
-var lock = &sync.Mutex{}
-
-type multiLog struct {
-	registered_loggers map[string]loggers.LoggerInterface
-}
-
-var mLogger *multiLog
-
-func getMultiLog() *multiLog {
-	if mLogger == nil {
-		lock.Lock()
-		defer lock.Unlock()
-		if mLogger == nil {
-			mLogger = &multiLog{
-				registered_loggers: map[string]loggers.LoggerInterface{
-					"": loggers.NewConsoleLoggerDefault(),
-				},
-			}
-		}
-	}
+var (
+	mLogger     *MultiLogger
+	mLoggerOnce sync.Once
+)
 
+// Returns the default [MultiLogger] used by the package level functions.
+func DefaultMultiLogger() *MultiLogger {
+	mLoggerOnce.Do(func() {
+		mLogger = New()
+	})
 	return mLogger
 }
 
-type fnLog func(logger loggers.LoggerInterface, level levels.LogLevel, arg any)
-type fnLogF func(logger loggers.LoggerInterface, format string, level levels.LogLevel, args ...interface{})
-
-func _log(logger loggers.LoggerInterface, level levels.LogLevel, arg any) {
-	logger.Log(level, arg)
-}
-
-func _logF(logger loggers.LoggerInterface, format string, level levels.LogLevel, args ...interface{}) {
-	logger.LogF(level, format, args...)
-}
-
-func logAll(fn fnLog, level levels.LogLevel, arg any) {
-	mLogger = getMultiLog()
-	for _, logger := range mLogger.registered_loggers {
-		fn(logger, level, arg)
-	}
-	if level == levels.Fatal {
-		panic(arg)
-	}
-}
-
-func logFAll(fn fnLogF, format string, level levels.LogLevel, args ...interface{}) {
-	mLogger = getMultiLog()
-	for _, logger := range mLogger.registered_loggers {
-		fn(logger, format, level, args...)
-	}
-}
-
 // Register an instance of an additional logger
 // that implements [loggers.LoggerInterface].
+// It is safe to register loggers while other goroutines are logging.
 func RegisterLogger(key string, logger loggers.LoggerInterface) error {
-	if len(key) == 0 {
-		return errors.Errorf("Empty key is not allowed for registering loggers.").Err
-	}
-	mLogger = getMultiLog()
-	mLogger.registered_loggers[key] = logger
-	return nil
+	return DefaultMultiLogger().Register(key, logger)
 }
 
 // Unregister an instance of logger by key.
+// It is safe to unregister loggers while other goroutines are logging.
 func UnregisterLogger(key string) error {
-	mLogger = getMultiLog()
-	logger := mLogger.registered_loggers[key]
-	if logger == nil {
-		return errors.Errorf("A logger for given key does not exists.").Err
-	}
-	delete(mLogger.registered_loggers, key)
-	return nil
+	return DefaultMultiLogger().Unregister(key)
 }
 
 // Return a registered logger instance by key.
 func GetLogger(key string) loggers.LoggerInterface {
-	mLogger = getMultiLog()
-	logger := mLogger.registered_loggers[key]
-	return logger
+	return DefaultMultiLogger().Get(key)
+}
+
+// Returns the sorted keys of all registered loggers including the key "" of the default logger.
+func GetLoggerKeys() []string {
+	return DefaultMultiLogger().Keys()
 }
 
 // Return the default instance of [loggers.ConsoleLogger].
 func DefaultLogger() loggers.LoggerInterface {
-	return GetLogger("")
+	return DefaultMultiLogger().DefaultLogger()
+}
+
+// Returns a child of the default [MultiLogger], which adds the given fields
+// to each message. The fields are given as alternating keys and values or as [loggers.Field].
+//
+//	reqLog := logger.With("request_id", id, "tenant", tenant)
+//	reqLog.Info("Request started")
+func With(keysAndValues ...any) *MultiLogger {
+	return DefaultMultiLogger().With(keysAndValues...)
 }
 
 // Log object in Debug level.
 func Debug(arg any) {
-	logAll(_log, levels.Debug, arg)
+	DefaultMultiLogger().Debug(arg)
 }
 
 // Log object in Trace level.
 func Trace(arg any) {
-	logAll(_log, levels.Trace, arg)
+	DefaultMultiLogger().Trace(arg)
 }
 
 // Log object in Info level.
 func Info(arg any) {
-	logAll(_log, levels.Info, arg)
+	DefaultMultiLogger().Info(arg)
 }
 
 // Log object in Warning level.
 func Warning(arg any) {
-	logAll(_log, levels.Warning, arg)
+	DefaultMultiLogger().Warning(arg)
 }
 
 // Log object in Error level.
 func Error(arg any) {
-	logAll(_log, levels.Error, arg)
+	DefaultMultiLogger().Error(arg)
 }
 
-// Log object in Fatal level and call Panic to exit.
+// Log object in Fatal level and call the [FatalHandler] (Panic by default).
 func Fatal(arg any) {
-	logAll(_log, levels.Fatal, arg)
+	DefaultMultiLogger().Fatal(arg)
 }
 
 // Log objects using format string in Debug level.
 func DebugF(format string, args ...interface{}) {
-	logFAll(_logF, format, levels.Debug, args...)
+	DefaultMultiLogger().DebugF(format, args...)
 }
 
 // Log objects using format string in Trace level.
 func TraceF(format string, args ...interface{}) {
-	logFAll(_logF, format, levels.Trace, args...)
+	DefaultMultiLogger().TraceF(format, args...)
 }
 
 // Log objects using format string in Info level.
 func InfoF(format string, args ...interface{}) {
-	logFAll(_logF, format, levels.Info, args...)
+	DefaultMultiLogger().InfoF(format, args...)
 }
 
 // Log objects using format string in Warning level.
 func WarningF(format string, args ...interface{}) {
-	logFAll(_logF, format, levels.Warning, args...)
+	DefaultMultiLogger().WarningF(format, args...)
 }
 
 // Log objects using format string in Error level.
 func ErrorF(format string, args ...interface{}) {
-	logFAll(_logF, format, levels.Error, args...)
+	DefaultMultiLogger().ErrorF(format, args...)
 }
 
-// Log objects using format string in Fatal level and call Panic to exit.
+// Log objects using format string in Fatal level and call the [FatalHandler] (Panic by default).
 func FatalF(format string, args ...interface{}) {
-	logFAll(_logF, format, levels.Fatal, args...)
+	DefaultMultiLogger().FatalF(format, args...)
+}
+
+// Log message with structured fields in Debug level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func DebugKV(msg string, keysAndValues ...any) {
+	DefaultMultiLogger().DebugKV(msg, keysAndValues...)
+}
+
+// Log message with structured fields in Trace level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func TraceKV(msg string, keysAndValues ...any) {
+	DefaultMultiLogger().TraceKV(msg, keysAndValues...)
+}
+
+// Log message with structured fields in Info level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func InfoKV(msg string, keysAndValues ...any) {
+	DefaultMultiLogger().InfoKV(msg, keysAndValues...)
+}
+
+// Log message with structured fields in Warning level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func WarningKV(msg string, keysAndValues ...any) {
+	DefaultMultiLogger().WarningKV(msg, keysAndValues...)
+}
+
+// Log message with structured fields in Error level.
+// The fields are given as alternating keys and values or as [loggers.Field].
+func ErrorKV(msg string, keysAndValues ...any) {
+	DefaultMultiLogger().ErrorKV(msg, keysAndValues...)
+}
+
+// Log message with structured fields in Fatal level and call the [FatalHandler] (Panic by default).
+// The fields are given as alternating keys and values or as [loggers.Field].
+func FatalKV(msg string, keysAndValues ...any) {
+	DefaultMultiLogger().FatalKV(msg, keysAndValues...)
 }
@@ -0,0 +1,150 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"strings"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// Returns a named child of the default [MultiLogger]. See [MultiLogger.Named].
+func Named(name string) *MultiLogger {
+	return DefaultMultiLogger().Named(name)
+}
+
+// Returns a named child [MultiLogger], which adds its name to each message
+// as field [loggers.NameKey]. The names of nested named loggers are joined
+// with ".", so l.Named("db").Named("pool") is named "db.pool".
+// The level of the messages of named loggers can be overridden per name
+// prefix with [MultiLogger.SetNamedLevel].
+//
+//	poolLog := logger.Named("db.pool")
+//	poolLog.DebugKV("Connection acquired", "idle", 3)
+func (l *MultiLogger) Named(name string) *MultiLogger {
+	if len(name) == 0 {
+		return l
+	}
+	if len(l.name) > 0 {
+		name = l.name + "." + name
+	}
+	fields := make([]loggers.Field, 0, len(l.fields)+1)
+	fields = append(fields, loggers.Field{Key: loggers.NameKey, Value: name})
+	for _, field := range l.fields {
+		if field.Key != loggers.NameKey {
+			fields = append(fields, field)
+		}
+	}
+	return &MultiLogger{m: l.m, fields: fields, name: name}
+}
+
+// Returns the name of this [MultiLogger] or "" if it is not named.
+func (l *MultiLogger) Name() string {
+	return l.name
+}
+
+// Overrides the level of the named children of the default [MultiLogger].
+// See [MultiLogger.SetNamedLevel].
+func SetNamedLevel(prefix string, level levels.LogLevel) {
+	DefaultMultiLogger().SetNamedLevel(prefix, level)
+}
+
+// Overrides the level of the messages of the named loggers, which names
+// are equal to the prefix or start with the prefix followed by ".".
+// The prefix "db" matches "db" and "db.pool", but not "dbx".
+// The longest matching prefix is used.
+//
+// The messages with the overridden level or above are printed by the loggers
+// implementing [loggers.OverrideLoggerInterface] with level Info or below
+// regardless of their own level, so Debug can be enabled for one subsystem
+// while the loggers stay at Info. The loggers with a higher level, e.g. the loggers
+// for errors only, keep filtering the messages by their own level.
+// The messages below the overridden level are not printed by any logger.
+//
+//	logger.SetNamedLevel("db.pool", levels.Debug)
+//	logger.SetNamedLevel("http", levels.Error)
+func (l *MultiLogger) SetNamedLevel(prefix string, level levels.LogLevel) {
+	l.updateNamedLevels(func(namedLevels map[string]levels.LogLevel) {
+		namedLevels[prefix] = level
+	})
+}
+
+// Removes the level override of the prefix in the default [MultiLogger].
+func ClearNamedLevel(prefix string) {
+	DefaultMultiLogger().ClearNamedLevel(prefix)
+}
+
+// Removes the level override of the prefix set by [MultiLogger.SetNamedLevel].
+func (l *MultiLogger) ClearNamedLevel(prefix string) {
+	l.updateNamedLevels(func(namedLevels map[string]levels.LogLevel) {
+		delete(namedLevels, prefix)
+	})
+}
+
+// Publishes a copy of the level overrides changed by "update".
+// It is safe to change the overrides while other goroutines are logging.
+func (l *MultiLogger) updateNamedLevels(update func(namedLevels map[string]levels.LogLevel)) {
+	m := l.m
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	next := make(map[string]levels.LogLevel)
+	if current := m.namedLevels.Load(); current != nil {
+		for prefix, level := range *current {
+			next[prefix] = level
+		}
+	}
+	update(next)
+	m.namedLevels.Store(&next)
+}
+
+// Returns the overridden level of the longest prefix matching the name of this logger.
+func (l *MultiLogger) namedLevel() (levels.LogLevel, bool) {
+	if len(l.name) == 0 {
+		return levels.All, false
+	}
+	namedLevels := l.m.namedLevels.Load()
+	if namedLevels == nil || len(*namedLevels) == 0 {
+		return levels.All, false
+	}
+	name := l.name
+	for {
+		if level, ok := (*namedLevels)[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return levels.All, false
+		}
+		name = name[:i]
+	}
+}
+
+// Passes the message to loggers implementing [loggers.OverrideLoggerInterface]
+// regardless of their level, if their level is [levels.Info] or below.
+// The loggers with a higher level, e.g. the loggers for errors only,
+// and other loggers are used like in [_logKV].
+func _logKVOverride(logger loggers.LoggerInterface, level levels.LogLevel, msg string, fields []loggers.Field) {
+	if logger.GetLevel() > levels.Info {
+		_logKV(logger, level, msg, fields)
+		return
+	}
+	if overrideLogger, ok := logger.(loggers.OverrideLoggerInterface); ok {
+		overrideLogger.LogKVOverride(level, msg, fields...)
+		return
+	}
+	_logKV(logger, level, msg, fields)
+}
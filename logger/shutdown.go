@@ -0,0 +1,79 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-errors/errors"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// Stops accepting messages, waits for the log calls in progress
+// and then flushes and closes all registered loggers.
+// The loggers implementing [loggers.FlusherInterface] are flushed
+// and the loggers implementing [io.Closer] are closed.
+//
+// Shutdown returns the errors of all loggers joined together, or the error
+// of the context if the loggers are not closed before its deadline.
+// No messages are logged after calling Shutdown.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	defer logger.Shutdown(ctx)
+func Shutdown(ctx context.Context) error {
+	return DefaultMultiLogger().Shutdown(ctx)
+}
+
+// Stops accepting messages and then flushes and closes
+// all loggers registered in this [MultiLogger], like [Shutdown].
+func (l *MultiLogger) Shutdown(ctx context.Context) error {
+	m := l.m
+	m.closed.Store(true)
+
+	done := make(chan error, 1)
+	go func() {
+		// The loggers are closed after the log calls using them are finished.
+		g := m.registered_loggers.Load()
+		m.drain(g)
+		var errs []error
+		for key, logger := range g.loggers {
+			if err := closeLogger(logger); err != nil {
+				errs = append(errs, errors.Errorf("Closing logger %q failed: %w", key, err).Err)
+			}
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Errorf("Closing loggers did not finish: %w", ctx.Err()).Err
+	}
+}
+
+func closeLogger(logger loggers.LoggerInterface) error {
+	var errs []error
+	if flusher, ok := logger.(loggers.FlusherInterface); ok {
+		errs = append(errs, flusher.Flush())
+	}
+	if closer, ok := logger.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,134 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// [SlogHandler] is a [slog.Handler], which passes the records
+// to all the loggers registered with [RegisterLogger]
+// or in the [MultiLogger] it is created by.
+// The attributes are passed as [loggers.Field] and the attributes
+// in groups get keys prefixed with the group names, e.g. "request.id".
+//
+// The time of the record is passed as field [loggers.TimeField],
+// so the loggers print it instead of the time of dispatch.
+//
+// Records with level [loggers.SlogLevelFatal] are logged in Fatal level,
+// but the handler never calls Panic.
+type SlogHandler struct {
+	logger *MultiLogger
+	fields []loggers.Field
+	prefix string
+}
+
+// Returns a [slog.Handler], which logs in all registered loggers.
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler()))
+func NewSlogHandler() *SlogHandler {
+	return DefaultMultiLogger().SlogHandler()
+}
+
+// Returns a [slog.Handler], which logs in the loggers registered in this [MultiLogger].
+func (l *MultiLogger) SlogHandler() *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// Reports if any of the registered loggers logs messages in the given level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	logLevel := loggers.LevelFromSlog(level)
+	if namedLevel, ok := h.logger.namedLevel(); ok {
+		return logLevel >= namedLevel && len(h.logger.m.receivers()) > 0
+	}
+	for _, logger := range h.logger.m.receivers() {
+		if logLevel >= logger.GetLevel() {
+			return true
+		}
+	}
+	return false
+}
+
+// Logs the record in all registered loggers.
+// The fields of the registered [ContextExtractor] functions are added before the attributes.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make([]loggers.Field, 0, len(h.fields)+record.NumAttrs())
+	fields = append(fields, h.fields...)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, attr)
+		return true
+	})
+	level := loggers.LevelFromSlog(record.Level)
+	fields = h.logger.withFields(h.logger.contextFields(ctx, fields))
+	if caller, ok := h.logger.callerFieldPC(record.PC); ok {
+		fields = append([]loggers.Field{caller}, fields...)
+	}
+	if !record.Time.IsZero() {
+		fields = append(fields, loggers.TimeField(record.Time))
+	}
+	if stack, ok := h.logger.stackField(level, nil, fields); ok {
+		fields = append(fields, stack)
+	}
+	h.logger.logFieldsAll(_logKV, level, record.Message, fields)
+	return nil
+}
+
+// Returns a new handler, which adds the attributes to each record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := make([]loggers.Field, 0, len(h.fields)+len(attrs))
+	fields = append(fields, h.fields...)
+	for _, attr := range attrs {
+		fields = appendAttr(fields, h.prefix, attr)
+	}
+	return &SlogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+}
+
+// Returns a new handler, which prefixes the keys
+// of the following attributes with the group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return &SlogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// Appends the attribute as field. The attributes
+// of a group are flattened with prefixed keys.
+func appendAttr(fields []loggers.Field, prefix string, attr slog.Attr) []loggers.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if len(attr.Key) > 0 {
+			groupPrefix = prefix + attr.Key + "."
+		}
+		for _, groupAttr := range attr.Value.Group() {
+			fields = appendAttr(fields, groupPrefix, groupAttr)
+		}
+		return fields
+	}
+	return append(fields, loggers.Field{Key: prefix + attr.Key, Value: attr.Value.Any()})
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
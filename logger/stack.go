@@ -0,0 +1,121 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// [StackTraceOptions] defines which messages get a stack trace
+// as field with key [loggers.StackKey]. The stack trace of an [errors.Error]
+// logged as object or field is used when present, otherwise the stack trace
+// is captured at the log call.
+type StackTraceOptions struct {
+	// Adds stack traces to the messages.
+	Enabled bool
+	// Messages in this level or higher get a stack trace (default levels.Error).
+	MinLevel levels.LogLevel
+	// Messages, which log an error as object or field,
+	// get a stack trace in any level.
+	Errors bool
+}
+
+// The maximum number of frames in a captured stack trace.
+const maxStackDepth = 64
+
+// The log calls of package "log/slog" are skipped when capturing the stack trace.
+const slogPackage = "log/slog."
+
+// Sets which messages logged by the default [MultiLogger] get a stack trace.
+//
+//	logger.SetStackTrace(logger.StackTraceOptions{Enabled: true, MinLevel: levels.Error})
+func SetStackTrace(options StackTraceOptions) {
+	DefaultMultiLogger().SetStackTrace(options)
+}
+
+// Sets which messages logged by this [MultiLogger] and its children get a stack trace.
+func (l *MultiLogger) SetStackTrace(options StackTraceOptions) {
+	if options.MinLevel == levels.All {
+		options.MinLevel = levels.Error
+	}
+	l.m.stackTrace.Store(&options)
+}
+
+// Returns the stack trace field for a message in the given level,
+// which logs the object "arg" and the fields, or false if
+// the message does not get a stack trace.
+func (l *MultiLogger) stackField(level levels.LogLevel, arg any, fields []loggers.Field) (loggers.Field, bool) {
+	options := l.m.stackTrace.Load()
+	if options == nil || !options.Enabled {
+		return loggers.Field{}, false
+	}
+	err := findError(arg, fields)
+	if level < options.MinLevel && !(options.Errors && err != nil) {
+		return loggers.Field{}, false
+	}
+	var stackErr *errors.Error
+	if errors.As(err, &stackErr) {
+		return loggers.F(loggers.StackKey, formatStack(stackErr.Callers())), true
+	}
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	return loggers.F(loggers.StackKey, formatStack(pcs[:n])), true
+}
+
+// Returns the object "arg" or the first field value, which is an error.
+func findError(arg any, fields []loggers.Field) error {
+	if err, ok := arg.(error); ok {
+		return err
+	}
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Formats the stack trace with function name and location of each call
+// on separate lines. The calls inside this package and "log/slog" are skipped.
+//
+//	main.handler
+//		/app/main.go:42
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, loggerPackage) && !strings.HasPrefix(frame.Function, slogPackage) {
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+		}
+		if !more {
+			return sb.String()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// The interval used by [WatchConfigFile] when the given interval is not positive.
+const DefaultWatchInterval = 5 * time.Second
+
+// [ConfigWatcher] polls a configuration file and applies the changes
+// to the registered loggers while the process keeps logging.
+// Create it with [WatchConfigFile] or [MultiLogger.WatchConfigFile].
+type ConfigWatcher struct {
+	logger   *MultiLogger
+	path     string
+	interval time.Duration
+	content  []byte
+	config   *Config
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Applies the configuration file like [ConfigureFromFile] and then polls the file
+// in the given interval and applies its changes to the loggers of the default [MultiLogger].
+// See [MultiLogger.WatchConfigFile].
+func WatchConfigFile(path string, interval time.Duration) (*ConfigWatcher, error) {
+	return DefaultMultiLogger().WatchConfigFile(path, interval)
+}
+
+// Applies the configuration file like [MultiLogger.ConfigureFromFile] and then
+// polls the file in the given interval and applies its changes to the loggers:
+//   - the changed levels are set to the registered loggers,
+//   - the loggers with other changes are built again and replaced in one step,
+//   - the added loggers are registered,
+//   - the removed loggers are unregistered,
+//   - the unchanged loggers are kept.
+//
+// The replaced and removed loggers are flushed and closed after the log calls
+// using them are finished, so no messages are lost or logged twice during the change.
+// If the changed file is not valid, the error is logged in Error level
+// and the last valid configuration stays in effect.
+//
+//	watcher, err := logger.WatchConfigFile("log.yaml", 10*time.Second)
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer watcher.Close()
+func (l *MultiLogger) WatchConfigFile(path string, interval time.Duration) (*ConfigWatcher, error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	watcher := &ConfigWatcher{
+		logger:   l,
+		path:     path,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := watcher.reload(); err != nil {
+		return nil, err
+	}
+	go watcher.run()
+	return watcher, nil
+}
+
+// Reads the configuration file and applies it if its content is changed.
+func (watcher *ConfigWatcher) reload() error {
+	content, err := os.ReadFile(watcher.path)
+	if err != nil {
+		return errors.Errorf("Reading configuration failed: %w", err).Err
+	}
+	if watcher.config != nil && bytes.Equal(content, watcher.content) {
+		return nil
+	}
+	config, err := parseConfig(watcher.path, content)
+	if err == nil {
+		err = watcher.logger.applyConfig(watcher.config, config)
+	}
+	// The content is remembered also when it is not valid,
+	// so the same error is not reported on each poll.
+	watcher.content = content
+	if err != nil {
+		return err
+	}
+	watcher.config = config
+	return nil
+}
+
+func (watcher *ConfigWatcher) run() {
+	defer close(watcher.done)
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-watcher.stop:
+			return
+		case <-ticker.C:
+			if err := watcher.reload(); err != nil {
+				watcher.logger.ErrorKV("Reloading logger configuration failed", "path", watcher.path, "error", err)
+			}
+		}
+	}
+}
+
+// Stops polling the configuration file.
+// The loggers registered by the watcher stay registered.
+func (watcher *ConfigWatcher) Close() error {
+	watcher.once.Do(func() {
+		close(watcher.stop)
+	})
+	<-watcher.done
+	return nil
+}
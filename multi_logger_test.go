@@ -182,7 +182,7 @@ func TestCustomizedFileLog(t *testing.T) {
 	defer logger.UnregisterLogger(key)
 
 	logger.DefaultLogger().Stop()
-	defer logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
 	assert.Equal(t, fileLogger.Level, level)
 	assert.Equal(t, logger.GetLogger(key).GetLevel(), fileLogger.Level)
 	logger.Error("Test log error message")
@@ -0,0 +1,130 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// Returns a new [logger.MultiLogger] with a JSON logger writing into the buffer
+// and a recording logger, which does not support level overrides. Both log in Info level.
+func newNamedMultiLogger(t *testing.T, buf *bytes.Buffer) (*logger.MultiLogger, *recordingLogger) {
+	l, r := newRecordingMultiLogger(t)
+	r.SetLevel(levels.Info)
+	if err := l.Register("json", loggers.NewJSONLogger(levels.Info, buf)); err != nil {
+		t.Fatal(err)
+	}
+	return l, r
+}
+
+func jsonMessages(t *testing.T, buf *bytes.Buffer) []string {
+	var messages []string
+	for _, entry := range decodeJSONLines(t, buf.String()) {
+		messages = append(messages, entry["msg"].(string))
+	}
+	buf.Reset()
+	return messages
+}
+
+func TestNamedLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l, r := newNamedMultiLogger(t, &buf)
+	pool := l.Named("db").Named("pool").With("tenant", "acme")
+	assert.Equal(t, "db.pool", pool.Name())
+	assert.Equal(t, "", l.Name())
+	assert.Same(t, l, l.Named(""))
+
+	pool.Debug("Debug message")
+	pool.InfoKV("Info message", "idle", 3)
+	entries := decodeJSONLines(t, buf.String())
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "Info message", entries[0]["msg"])
+		assert.Equal(t, "db.pool", entries[0]["logger"])
+		assert.Equal(t, "acme", entries[0]["tenant"])
+		assert.Equal(t, float64(3), entries[0]["idle"])
+	}
+	assert.Equal(t, []string{"Info message logger=db.pool tenant=acme idle=3"}, r.Messages())
+}
+
+func TestNamedLevelOverride(t *testing.T) {
+	var buf bytes.Buffer
+	l, r := newNamedMultiLogger(t, &buf)
+	pool := l.Named("db.pool")
+	conn := l.Named("db").Named("conn")
+
+	l.SetNamedLevel("db", levels.Debug)
+	pool.Debug("Pool debug")
+	conn.DebugF("Conn debug %d", 1)
+	l.Named("dbx").Debug("Other debug")
+	l.Debug("Root debug")
+	assert.Equal(t, []string{"Pool debug", "Conn debug 1"}, jsonMessages(t, &buf))
+	// Loggers without support of overrides keep their own level.
+	assert.Empty(t, r.Messages())
+
+	// The longest prefix is used.
+	l.SetNamedLevel("db.pool", levels.Error)
+	pool.Warning("Pool warning")
+	pool.ErrorKV("Pool error", "idle", 0)
+	conn.Trace("Conn trace")
+	assert.Equal(t, []string{"Pool error", "Conn trace"}, jsonMessages(t, &buf))
+
+	l.ClearNamedLevel("db")
+	l.ClearNamedLevel("db.pool")
+	pool.Debug("Pool debug")
+	conn.Info("Conn info")
+	assert.Equal(t, []string{"Conn info"}, jsonMessages(t, &buf))
+}
+
+func TestNamedLevelOverrideStoppedAndAsync(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := newNamedMultiLogger(t, &buf)
+	pool := l.Named("db.pool")
+	l.SetNamedLevel("db", levels.Debug)
+
+	l.Get("json").Stop()
+	pool.Debug("Stopped debug")
+	assert.Empty(t, buf.String())
+
+	var asyncBuf bytes.Buffer
+	asyncLogger := loggers.NewAsyncLogger(loggers.NewJSONLogger(levels.Info, &asyncBuf), loggers.AsyncOptions{})
+	assert.NoError(t, l.Register("async", asyncLogger))
+	pool.Debug("Async debug")
+	slog.New(pool.SlogHandler()).Debug("Slog debug", "idle", 3)
+	assert.NoError(t, asyncLogger.Close())
+	assert.Equal(t, []string{"Async debug", "Slog debug"}, jsonMessages(t, &asyncBuf))
+}
+
+func TestNamedLevelOverrideKeepsHigherLevels(t *testing.T) {
+	var buf, alertsBuf bytes.Buffer
+	l, _ := newNamedMultiLogger(t, &buf)
+	assert.NoError(t, l.Register("alerts", loggers.NewJSONLogger(levels.Error, &alertsBuf)))
+	db := l.Named("db")
+	l.SetNamedLevel("db", levels.Debug)
+
+	db.Debug("Debug message")
+	db.Error("Error message")
+	slog.New(db.SlogHandler()).Debug("Slog debug")
+	assert.Equal(t, []string{"Debug message", "Error message", "Slog debug"}, jsonMessages(t, &buf))
+	// Loggers with a level above Info keep their own level.
+	assert.Equal(t, []string{"Error message"}, jsonMessages(t, &alertsBuf))
+}
@@ -0,0 +1,113 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func TestStandardLogIsNotChanged(t *testing.T) {
+	var buf bytes.Buffer
+	oldOutput := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(oldOutput)
+
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+	}
+	key := "std_log_file_key"
+	err := logger.RegisterLogger(key, loggers.NewFileLogger(levels.Info, "", fileOptions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	content := readConsole(func() {
+		logger.Info("Test info log message")
+		log.Print("Standard log message")
+	})
+	removeLogFiles(t, fileOptions)
+
+	assert.Contains(t, content, "INFO: [Test info log message]")
+	assert.NotContains(t, content, "Standard log message")
+	assert.Equal(t, &buf, log.Writer())
+	assert.Contains(t, buf.String(), "Standard log message")
+	assert.NotContains(t, buf.String(), "Test info log message")
+}
+
+func TestConsoleAndFileOutputsDoNotMix(t *testing.T) {
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+	}
+	fileLogger := loggers.NewFileLogger(levels.Info, "", fileOptions)
+	consoleLogger := loggers.NewConsoleLogger(levels.Info, "")
+
+	const count = 100
+	content := readConsole(func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				consoleLogger.LogF(levels.Info, "console message %d", i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				fileLogger.LogF(levels.Info, "file message %d", i)
+			}
+		}()
+		wg.Wait()
+	})
+	fileContent := removeLogFiles(t, fileOptions)
+
+	assert.NotContains(t, content, "file message")
+	assert.NotContains(t, fileContent, "console message")
+	assert.Equal(t, count, strings.Count(content, "console message"))
+	assert.Equal(t, count, strings.Count(fileContent, "file message"))
+}
+
+// Removes the log files created with the given options
+// and returns their joined content.
+func removeLogFiles(t *testing.T, fileOptions loggers.FileOptions) string {
+	pattern := fmt.Sprintf("%s*%s", fileOptions.FilePrefix, fileOptions.FileExtension)
+	logFiles, err := walkMatch(t, fileOptions.Directory, pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var content strings.Builder
+	for _, f := range logFiles {
+		content.WriteString(readFileContent(t, f))
+		os.Remove(f)
+	}
+	return content.String()
+}
@@ -0,0 +1,91 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func newPrettyEntry() loggers.Entry {
+	return loggers.Entry{
+		Time:    time.Date(2024, 1, 14, 10, 5, 30, 123000000, time.UTC),
+		Level:   levels.Warning,
+		Message: "Disk is almost full",
+		Caller:  "main.go:12",
+		Fields:  []loggers.Field{loggers.F("free", "1 GB"), loggers.F("path", "/var")},
+	}
+}
+
+func TestPrettyFormatter(t *testing.T) {
+	entry := newPrettyEntry()
+	assert.Equal(t,
+		"10:05:30.123 WARN  main.go:12 Disk is almost full                      free=\"1 GB\" path=/var\n",
+		string(loggers.PrettyFormatter{}.Format(entry)))
+
+	entry = loggers.Entry{Time: entry.Time, Level: levels.Error, Message: "Failed", Error: errors.Errorf("no space").Err, Stack: "main.main\n\t/app/main.go:12"}
+	assert.Equal(t,
+		"10:05:30.123 ERROR Failed                                   error=\"no space\"\n\tmain.main\n\t\t/app/main.go:12\n",
+		string(loggers.PrettyFormatter{}.Format(entry)))
+
+	entry = loggers.Entry{Time: entry.Time, Level: levels.Info, Message: "Message"}
+	assert.Equal(t, "10:05:30.123 INFO  Message\n", string(loggers.PrettyFormatter{}.Format(entry)))
+}
+
+func TestPrettyFormatterColors(t *testing.T) {
+	line := string(loggers.PrettyFormatter{Color: true}.Format(newPrettyEntry()))
+	assert.Equal(t,
+		"10:05:30.123 \x1b[33mWARN \x1b[0m \x1b[90mmain.go:12\x1b[0m Disk is almost full                      \x1b[33mfree=\x1b[0m\"1 GB\" \x1b[33mpath=\x1b[0m/var\n",
+		line)
+}
+
+func TestPrettyConsoleLoggerColorModes(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	for mode, colored := range map[loggers.ColorMode]bool{
+		loggers.ColorAuto:   false,
+		loggers.ColorAlways: true,
+		loggers.ColorNever:  false,
+	} {
+		content := readConsole(func() {
+			l := logger.New()
+			l.DefaultLogger().Stop()
+			l.Register("pretty", loggers.NewPrettyConsoleLogger(levels.Debug, mode))
+			l.DebugKV("Message", "user", 42)
+		})
+		assert.Equal(t, colored, strings.Contains(content, "\x1b["), content)
+		assert.Regexp(t, regexp.MustCompile(`^\d\d:\d\d:\d\d\.\d{3} .*DEBUG.* Message {33}.*user=.*42\n$`), content)
+	}
+}
+
+func TestPrettyConsoleLoggerNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	content := readConsole(func() {
+		l := logger.New()
+		l.DefaultLogger().Stop()
+		l.Register("pretty", loggers.NewPrettyConsoleLogger(levels.Info, loggers.ColorAuto))
+		l.Info("Message")
+	})
+	assert.NotContains(t, content, "\x1b[")
+	assert.Contains(t, content, "INFO  Message\n")
+}
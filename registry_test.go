@@ -0,0 +1,162 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// countingLogger counts the messages instead of printing them.
+type countingLogger struct {
+	loggers.LoggerType
+	count atomic.Int64
+}
+
+func newCountingLogger() *countingLogger {
+	return &countingLogger{LoggerType: loggers.LoggerType{Level: levels.All}}
+}
+
+func (logger *countingLogger) Log(level levels.LogLevel, arg any) {
+	if logger.IsLogAllowed(level) {
+		logger.count.Add(1)
+	}
+}
+
+func (logger *countingLogger) LogF(level levels.LogLevel, format string, args ...interface{}) {
+	if logger.IsLogAllowed(level) {
+		logger.count.Add(1)
+	}
+}
+
+func TestRegisterLoggerReceivesMessages(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	c := newCountingLogger()
+	key := "counting_key"
+	err := logger.RegisterLogger(key, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("Message 1")
+	logger.InfoF("Message %d", 2)
+
+	err = logger.UnregisterLogger(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("Message 3")
+
+	assert.Equal(t, int64(2), c.count.Load())
+	assert.Nil(t, logger.GetLogger(key))
+	assert.Error(t, logger.UnregisterLogger(key))
+	assert.Error(t, logger.RegisterLogger("", c))
+}
+
+func TestRegistryConcurrentStress(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	const workers = 8
+	const iterations = 200
+
+	shared := newCountingLogger()
+	sharedKey := "stress_shared_key"
+	err := logger.RegisterLogger(sharedKey, shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(sharedKey)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(3)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				key := fmt.Sprintf("stress_%d_%d", w, i)
+				assert.NoError(t, logger.RegisterLogger(key, newCountingLogger()))
+				assert.NotNil(t, logger.GetLogger(key))
+				assert.NoError(t, logger.UnregisterLogger(key))
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				logger.Info("Stress info message")
+				logger.WarningF("Stress warning message %d", i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				_ = logger.GetLogger(sharedKey)
+				_ = logger.DefaultLogger()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(workers*iterations*2), shared.count.Load())
+	for w := 0; w < workers; w++ {
+		assert.Nil(t, logger.GetLogger(fmt.Sprintf("stress_%d_0", w)))
+	}
+}
+
+func TestRegistryConcurrentReplace(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	key := "stress_replace_key"
+	first := newCountingLogger()
+	second := newCountingLogger()
+	err := logger.RegisterLogger(key, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			if i%2 == 0 {
+				assert.NoError(t, logger.RegisterLogger(key, second))
+			} else {
+				assert.NoError(t, logger.RegisterLogger(key, first))
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			logger.Error("Stress error message")
+		}
+	}()
+	wg.Wait()
+
+	// Replacing a logger must never lose or duplicate a message.
+	assert.Equal(t, int64(500), first.count.Load()+second.count.Load())
+}
@@ -0,0 +1,258 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// fakeClock is a clock, which is moved forward only by the tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 14, 10, 30, 0, 0, time.Local)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newRotationOptions(t *testing.T, clock *fakeClock) loggers.FileOptions {
+	return loggers.FileOptions{
+		Directory:     t.TempDir(),
+		FilePrefix:    "rLog",
+		FileExtension: ".log",
+		Clock:         clock.Now,
+	}
+}
+
+// Returns the names of all files in the directory sorted by name.
+func listFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func countSuffix(names []string, suffix string) int {
+	count := 0
+	for _, name := range names {
+		if strings.HasSuffix(name, suffix) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRotateBySize(t *testing.T) {
+	clock := newFakeClock()
+	options := newRotationOptions(t, clock)
+	options.MaxSize = 100
+	fileLogger := loggers.NewFileLogger(levels.Info, "%s", options)
+	defer fileLogger.Close()
+
+	for i := 0; i < 6; i++ {
+		// Each line is 20 bytes for the time and 30 bytes for the message.
+		fileLogger.Log(levels.Info, strings.Repeat("x", 29))
+		clock.Add(time.Second)
+	}
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 3)
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(options.Directory, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.LessOrEqual(t, info.Size(), options.MaxSize)
+	}
+	assert.Contains(t, names[0], "-20240114T103002.000.log")
+	assert.Contains(t, names[1], "-20240114T103004.000.log")
+}
+
+func TestRotateHourlyAndDaily(t *testing.T) {
+	for _, rollover := range []loggers.Rollover{loggers.RolloverHourly, loggers.RolloverDaily} {
+		clock := newFakeClock()
+		options := newRotationOptions(t, clock)
+		options.Rollover = rollover
+		fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+		defer fileLogger.Close()
+
+		fileLogger.Log(levels.Info, "Message 1")
+		clock.Add(20 * time.Minute)
+		fileLogger.Log(levels.Info, "Message 2")
+		clock.Add(20 * time.Minute)
+		fileLogger.Log(levels.Info, "Message 3")
+		clock.Add(24 * time.Hour)
+		fileLogger.Log(levels.Info, "Message 4")
+
+		names := listFiles(t, options.Directory)
+		if rollover == loggers.RolloverHourly {
+			assert.Len(t, names, 3)
+			assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[0])), "Message 2")
+			assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[1])), "Message 3")
+		} else {
+			assert.Len(t, names, 2)
+			content := readFileContent(t, filepath.Join(options.Directory, names[0]))
+			assert.Contains(t, content, "Message 1")
+			assert.Contains(t, content, "Message 3")
+		}
+		assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[len(names)-1])), "Message 4")
+	}
+}
+
+func TestRotateMaxBackups(t *testing.T) {
+	clock := newFakeClock()
+	options := newRotationOptions(t, clock)
+	options.Rollover = loggers.RolloverHourly
+	options.MaxBackups = 2
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+
+	for i := 0; i < 5; i++ {
+		fileLogger.LogF(levels.Info, "Message %d", i)
+		clock.Add(time.Hour)
+	}
+	// The old backups are removed in the background until Close.
+	assert.NoError(t, fileLogger.Close())
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 3)
+	assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[0])), "Message 2")
+	assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[1])), "Message 3")
+	assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[2])), "Message 4")
+}
+
+func TestRotateMaxBackupsOfPreviousProcesses(t *testing.T) {
+	clock := newFakeClock()
+	options := newRotationOptions(t, clock)
+	options.Rollover = loggers.RolloverHourly
+	options.MaxBackups = 2
+	// Backups of previous processes and files with other prefixes.
+	for _, name := range []string{
+		"rLog_1-20240101T000000.000.log",
+		"rLog_1_7-20240101T010000.000.log.gz",
+		"rLog_x-20240101T000000.000.log",
+		"rLogs_1-20240101T000000.000.log",
+		"rLog_1-20240101T000000.000.txt",
+	} {
+		if err := os.WriteFile(filepath.Join(options.Directory, name), []byte("Old message\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+
+	fileLogger.Log(levels.Info, "Message 0")
+	clock.Add(time.Hour)
+	fileLogger.Log(levels.Info, "Message 1")
+	assert.NoError(t, fileLogger.Close())
+
+	names := listFiles(t, options.Directory)
+	assert.NotContains(t, names, "rLog_1-20240101T000000.000.log")
+	assert.Contains(t, names, "rLog_1_7-20240101T010000.000.log.gz")
+	assert.Contains(t, names, "rLog_x-20240101T000000.000.log")
+	assert.Contains(t, names, "rLogs_1-20240101T000000.000.log")
+	assert.Contains(t, names, "rLog_1-20240101T000000.000.txt")
+	assert.Len(t, names, 6)
+}
+
+func TestRotateMaxAge(t *testing.T) {
+	clock := newFakeClock()
+	options := newRotationOptions(t, clock)
+	options.Rollover = loggers.RolloverDaily
+	options.MaxAge = 36 * time.Hour
+	fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+	defer fileLogger.Close()
+
+	for i := 0; i < 5; i++ {
+		fileLogger.LogF(levels.Info, "Message %d", i)
+		clock.Add(24 * time.Hour)
+	}
+
+	assert.NoError(t, fileLogger.Close())
+
+	// Backups are named with the rotation time, so only the
+	// backups rotated in the last 36 hours are retained.
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 3)
+	assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[0])), "Message 2")
+	assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[1])), "Message 3")
+}
+
+func TestRotateCompress(t *testing.T) {
+	clock := newFakeClock()
+	options := newRotationOptions(t, clock)
+	options.MaxSize = 60
+	options.Compress = true
+	fileLogger := loggers.NewFileLogger(levels.Info, "%s", options)
+	defer fileLogger.Close()
+
+	fileLogger.Log(levels.Info, "Compressed message")
+	clock.Add(time.Second)
+	fileLogger.Log(levels.Info, "Current message")
+	// The backup is compressed in the background until Close.
+	assert.NoError(t, fileLogger.Close())
+
+	names := listFiles(t, options.Directory)
+	assert.Len(t, names, 2)
+	assert.Equal(t, 1, countSuffix(names, ".log.gz"))
+
+	gzFile, err := os.Open(filepath.Join(options.Directory, names[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gzFile.Close()
+	gz, err := gzip.NewReader(gzFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(content), "Compressed message")
+	assert.Contains(t, readFileContent(t, filepath.Join(options.Directory, names[1])), "Current message")
+}
@@ -0,0 +1,158 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+// The environment variable, which selects the scenario
+// run by a test in a subprocess.
+const subprocessEnv = "GO_MULTI_LOG_SUBPROCESS"
+
+// Runs the test "name" in a subprocess with the given scenario,
+// so it can change the package level state or exit the process.
+func runSubprocess(t *testing.T, name, scenario string, env ...string) (string, int) {
+	cmd := exec.Command(os.Args[0], "-test.run=^"+name+"$", "-test.v")
+	cmd.Env = append(os.Environ(), subprocessEnv+"="+scenario)
+	cmd.Env = append(cmd.Env, env...)
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(output), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output), 0
+}
+
+// closeLogger is a logger, which fails or blocks when it is closed.
+type closeLogger struct {
+	recordingLogger
+	err   error
+	block chan struct{}
+}
+
+func (logger *closeLogger) Close() error {
+	if logger.block != nil {
+		<-logger.block
+	}
+	return logger.err
+}
+
+// gateCloseLogger records the messages printed before it is closed.
+type gateCloseLogger struct {
+	*gateLogger
+	printedBeforeClose chan []string
+}
+
+func (logger *gateCloseLogger) Close() error {
+	logger.printedBeforeClose <- logger.Messages()
+	return nil
+}
+
+func TestShutdownWaitsForLogCalls(t *testing.T) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	gate := &gateCloseLogger{gateLogger: newGateLogger(), printedBeforeClose: make(chan []string, 1)}
+	l.Register("gate", gate)
+
+	go l.Info("Message 1")
+	<-gate.started
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Shutdown finished before the log call")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(gate.gate)
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{"Message 1"}, <-gate.printedBeforeClose)
+}
+
+func TestShutdown(t *testing.T) {
+	for _, scenario := range []string{"flush", "errors", "deadline"} {
+		output, code := runSubprocess(t, "TestShutdownSubprocess", scenario, "LOG_DIR="+t.TempDir())
+		assert.Equal(t, 0, code, output)
+		assert.Contains(t, output, "--- PASS: TestShutdownSubprocess", output)
+	}
+}
+
+func TestShutdownSubprocess(t *testing.T) {
+	scenario := os.Getenv(subprocessEnv)
+	if len(scenario) == 0 {
+		t.Skip("runs only in a subprocess started by TestShutdown")
+	}
+	logger.DefaultLogger().Stop()
+
+	switch scenario {
+	case "flush":
+		options := newBufferedOptions(t)
+		options.Directory = os.Getenv("LOG_DIR")
+		fileLogger := loggers.NewFileLogger(levels.Info, "", options)
+		err := logger.RegisterLogger("async_file", loggers.NewAsyncLogger(fileLogger, loggers.AsyncOptions{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			logger.InfoF("Message %d", i)
+		}
+		assert.NoError(t, logger.Shutdown(context.Background()))
+		logger.Info("Message after shutdown")
+
+		content := readDirContent(t, options.Directory)
+		assert.Equal(t, 100, strings.Count(content, "\n"))
+		assert.Contains(t, content, "Message 99")
+		assert.NotContains(t, content, "Message after shutdown")
+
+	case "errors":
+		logger.RegisterLogger("first", &closeLogger{err: errors.Errorf("first failed").Err})
+		logger.RegisterLogger("second", &closeLogger{err: errors.Errorf("second failed").Err})
+		logger.RegisterLogger("third", &closeLogger{})
+		err := logger.Shutdown(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `"first"`)
+		assert.Contains(t, err.Error(), "first failed")
+		assert.Contains(t, err.Error(), `"second"`)
+		assert.Contains(t, err.Error(), "second failed")
+		assert.NotContains(t, err.Error(), `"third"`)
+
+	case "deadline":
+		block := make(chan struct{})
+		defer close(block)
+		logger.RegisterLogger("blocking", &closeLogger{block: block})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := logger.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+}
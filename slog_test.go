@@ -0,0 +1,162 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func TestSlogLevels(t *testing.T) {
+	for _, level := range []levels.LogLevel{levels.Debug, levels.Trace, levels.Info, levels.Warning, levels.Error, levels.Fatal} {
+		assert.Equal(t, level, loggers.LevelFromSlog(loggers.SlogLevel(level)))
+	}
+	assert.Equal(t, levels.Debug, loggers.LevelFromSlog(slog.LevelDebug-8))
+	assert.Equal(t, levels.Trace, loggers.LevelFromSlog(slog.LevelInfo-1))
+	assert.Equal(t, levels.Info, loggers.LevelFromSlog(slog.LevelInfo+2))
+	assert.Equal(t, levels.Fatal, loggers.LevelFromSlog(slog.LevelError+10))
+}
+
+func TestSlogHandler(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	var buf bytes.Buffer
+	key := "slog_json_key"
+	err := logger.RegisterLogger(key, loggers.NewJSONLogger(levels.Info, &buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	handler := logger.NewSlogHandler()
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelInfo))
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelDebug))
+
+	slogger := slog.New(handler).With("service", "api").WithGroup("request")
+	slogger.Debug("Skipped message")
+	slogger.Info("Request done", "id", 7, slog.Group("user", "name", "Michael"))
+	slogger.Log(context.Background(), loggers.SlogLevelFatal, "Fatal message")
+
+	entries := decodeJSONLines(t, buf.String())
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Info", entries[0]["level"])
+	assert.Equal(t, "Request done", entries[0]["msg"])
+	assert.Equal(t, "api", entries[0]["service"])
+	assert.Equal(t, float64(7), entries[0]["request.id"])
+	assert.Equal(t, "Michael", entries[0]["request.user.name"])
+	assert.Equal(t, "Fatal", entries[1]["level"])
+}
+
+func TestSlogHandlerWithoutFieldsSupport(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	r := newRecordingLogger()
+	key := "slog_recording_key"
+	err := logger.RegisterLogger(key, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	slog.New(logger.NewSlogHandler()).Warn("Disk is almost full", "free", "1 GB")
+	assert.Equal(t, []string{`Disk is almost full free="1 GB"`}, r.Messages())
+}
+
+func TestSlogLogger(t *testing.T) {
+	logger.DefaultLogger().Stop()
+	defer logger.DefaultLogger().Start()
+
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	key := "slog_logger_key"
+	err := logger.RegisterLogger(key, loggers.NewSlogLogger(levels.Trace, handler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.UnregisterLogger(key)
+
+	logger.Debug("Skipped message")
+	logger.TraceF("Person: %v", person{Name: "Michael"})
+	logger.ErrorKV("Payment failed", "order", 1001)
+
+	entries := decodeJSONLines(t, buf.String())
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Person: {Michael}", entries[0]["msg"])
+	assert.Equal(t, "DEBUG+2", entries[0]["level"])
+	assert.Equal(t, "Payment failed", entries[1]["msg"])
+	assert.Equal(t, "ERROR", entries[1]["level"])
+	assert.Equal(t, float64(1001), entries[1]["order"])
+}
+
+func TestSlogHandlerRecordTime(t *testing.T) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	var jsonBuf, slogBuf bytes.Buffer
+	options := newBufferedOptions(t)
+	assert.NoError(t, l.Register("json", loggers.NewJSONLogger(levels.Info, &jsonBuf)))
+	assert.NoError(t, l.Register("file", loggers.NewFileLogger(levels.Info, "", options)))
+	assert.NoError(t, l.Register("slog", loggers.NewSlogLogger(levels.Info, slog.NewJSONHandler(&slogBuf, nil))))
+	r := newRecordingLogger()
+	assert.NoError(t, l.Register("recording", r))
+
+	at := time.Date(2001, 2, 3, 4, 5, 6, 0, time.Local)
+	record := slog.NewRecord(at, slog.LevelInfo, "Old message", 0)
+	record.AddAttrs(slog.Int("user", 42))
+	assert.NoError(t, l.SlogHandler().Handle(context.Background(), record))
+	assert.NoError(t, l.Shutdown(context.Background()))
+
+	entries := decodeJSONLines(t, jsonBuf.String())
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, at.Format(time.RFC3339Nano), entries[0]["time"])
+	}
+	assert.Equal(t, "2001/02/03 04:05:06 INFO: [Old message] user=42\n", readDirContent(t, options.Directory))
+	slogEntries := decodeJSONLines(t, slogBuf.String())
+	if assert.Len(t, slogEntries, 1) {
+		assert.Equal(t, at.Format(time.RFC3339Nano), slogEntries[0]["time"])
+	}
+	// The time is not rendered into the messages of loggers without fields support.
+	assert.Equal(t, []string{"Old message user=42"}, r.Messages())
+}
+
+func TestTimeFieldOfCaller(t *testing.T) {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	var jsonBuf bytes.Buffer
+	assert.NoError(t, l.Register("json", loggers.NewJSONLogger(levels.Info, &jsonBuf)))
+	r := newRecordingLogger()
+	assert.NoError(t, l.Register("recording", r))
+
+	// Only the time of slog records is used as the time of the message.
+	deadline := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.InfoKV("Job scheduled", "time", deadline, "id", 1)
+
+	assert.Equal(t, []string{"Job scheduled time=\"2030-01-01 00:00:00 +0000 UTC\" id=1"}, r.Messages())
+	line := jsonBuf.String()
+	assert.Equal(t, 2, strings.Count(line, `"time":`), line)
+	assert.False(t, strings.HasPrefix(line, `{"time":"2030`), line)
+	assert.Contains(t, line, `"time":"2030-01-01T00:00:00Z","id":1}`)
+}
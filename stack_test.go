@@ -0,0 +1,97 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+const testPackage = "github.com/takecontrolsoft/go_multi_log."
+
+func newStackError() error {
+	return errors.New("stack error")
+}
+
+// Returns a new [logger.MultiLogger], which logs in JSON into the buffer.
+func newJSONMultiLogger(buf *bytes.Buffer) *logger.MultiLogger {
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	l.Register("json", loggers.NewJSONLogger(levels.All, buf))
+	return l
+}
+
+func TestStackTraceMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := newJSONMultiLogger(&buf)
+	l.Error("Disabled")
+	l.SetStackTrace(logger.StackTraceOptions{Enabled: true})
+	l.SetFatalHandler(func(arg any) {})
+	l.Warning("Warning message")
+	l.ErrorF("Error message %d", 1)
+	l.FatalKV("Fatal message", "user", 42)
+	l.With("request_id", 7).Error("Child message")
+	slog.New(l.SlogHandler()).Error("Slog message")
+
+	lines := decodeJSONLines(t, buf.String())
+	assert.Len(t, lines, 6)
+	assert.NotContains(t, lines[0], loggers.StackKey)
+	assert.NotContains(t, lines[1], loggers.StackKey)
+	for _, line := range lines[2:] {
+		stack := line[loggers.StackKey].(string)
+		assert.True(t, strings.HasPrefix(stack, testPackage+"TestStackTraceMinLevel\n\t"), stack)
+		assert.Contains(t, stack, "stack_test.go:")
+		assert.NotContains(t, stack, "go_multi_log/logger.")
+		assert.NotContains(t, stack, "log/slog.")
+	}
+}
+
+func TestStackTraceErrors(t *testing.T) {
+	var buf bytes.Buffer
+	l := newJSONMultiLogger(&buf)
+	l.SetStackTrace(logger.StackTraceOptions{Enabled: true, MinLevel: levels.Fatal, Errors: true})
+	l.Info(newStackError())
+	l.InfoKV("Request failed", "error", errors.Errorf("plain error").Err)
+	l.Error("No error object")
+
+	lines := decodeJSONLines(t, buf.String())
+	assert.Len(t, lines, 3)
+	// The stack trace of the error starts where it was created.
+	assert.True(t, strings.HasPrefix(lines[0][loggers.StackKey].(string), testPackage+"newStackError\n\t"))
+	assert.True(t, strings.HasPrefix(lines[1][loggers.StackKey].(string), testPackage+"TestStackTraceErrors\n\t"))
+	assert.NotContains(t, lines[2], loggers.StackKey)
+}
+
+func TestStackTraceConsole(t *testing.T) {
+	l := logger.New()
+	l.SetStackTrace(logger.StackTraceOptions{Enabled: true})
+	content := readConsole(func() {
+		l.ErrorKV("Error message", "user", 42)
+	})
+	lines := strings.Split(content, "\n")
+	assert.True(t, strings.HasSuffix(lines[0], "ERROR: [Error message] user=42"), lines[0])
+	assert.Equal(t, "\t"+testPackage+"TestStackTraceConsole.func1", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "\t\t"), lines[2])
+	assert.Contains(t, lines[2], "stack_test.go:")
+}
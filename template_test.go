@@ -0,0 +1,124 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func TestParseTemplateErrors(t *testing.T) {
+	for text, expected := range map[string]string{
+		"{level} {msg}":        "unknown placeholder {msg}",
+		"{level} {message":     `has not closed "{" at position 8`,
+		"{level}} {message}":   `has not opened "}" at position 7`,
+		"{time:} {message}":    "placeholder {time:} has empty time layout",
+		"{level:lower}":        "placeholder {level} does not support options",
+		"{message} {fields:x}": "placeholder {fields} does not support options",
+	} {
+		template, err := loggers.ParseTemplate(text)
+		assert.Nil(t, template, text)
+		if assert.Error(t, err, text) {
+			assert.Contains(t, err.Error(), expected, text)
+		}
+	}
+}
+
+func TestTemplateFormat(t *testing.T) {
+	entry := loggers.Entry{
+		Time:    time.Date(2024, 1, 14, 10, 5, 30, 123000000, time.UTC),
+		Level:   levels.Warning,
+		Message: "Disk is almost full",
+		Caller:  "main.go:12",
+		Fields:  []loggers.Field{loggers.F("free", "1 GB")},
+	}
+	for text, expected := range map[string]string{
+		"{time:2006-01-02T15:04:05.000Z07:00} {level} {caller} {message} {fields}": "2024-01-14T10:05:30.123Z WARNING main.go:12 Disk is almost full free=\"1 GB\"\n",
+		"{time} {level}: [{message}]":   "2024/01/14 10:05:30 WARNING: [Disk is almost full]\n",
+		"{{{level}}} {message}":         "{WARNING} Disk is almost full\n",
+		"{fields} | {message} {caller}": "free=\"1 GB\" | Disk is almost full main.go:12\n",
+	} {
+		template, err := loggers.ParseTemplate(text)
+		if assert.NoError(t, err, text) {
+			assert.Equal(t, text, template.String())
+			assert.Equal(t, expected, string(template.Format(entry)), text)
+		}
+	}
+
+	template, _ := loggers.ParseTemplate("{level} {caller} {message} {fields}")
+	entry.Caller = ""
+	entry.Fields = nil
+	assert.Equal(t, "WARNING Disk is almost full\n", string(template.Format(entry)))
+
+	entry.Stack = "main.main\n\t/app/main.go:12"
+	assert.Equal(t, "WARNING Disk is almost full\n\tmain.main\n\t\t/app/main.go:12\n", string(template.Format(entry)))
+}
+
+func TestConsoleLoggerTemplate(t *testing.T) {
+	_, err := loggers.NewConsoleLoggerTemplate(levels.Info, "{level} {msg}")
+	assert.Error(t, err)
+
+	consoleLogger, err := loggers.NewConsoleLoggerTemplate(levels.Info, "{time:15:04:05.000} {level} {caller} {message} {fields}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := logger.New()
+	l.DefaultLogger().Stop()
+	l.Register("template", consoleLogger)
+	l.SetCallerMode(logger.CallerFile)
+
+	var expected []string
+	content := readConsole(func() {
+		expected = append(expected, callerLine(1))
+		l.WarningKV("Disk is almost full", "free", "1 GB")
+		expected = append(expected, callerLine(1))
+		l.InfoF("Message %d", 2)
+		l.Debug("Skipped message")
+	})
+	assert.Regexp(t, regexp.MustCompile(`^\d\d:\d\d:\d\d\.\d{3} WARNING `+regexp.QuoteMeta(expected[0])+` Disk is almost full free="1 GB"\n`+
+		`\d\d:\d\d:\d\d\.\d{3} INFO `+regexp.QuoteMeta(expected[1])+` Message 2\n$`), content)
+	assert.NotContains(t, content, "Skipped message")
+}
+
+func TestFileLoggerTemplate(t *testing.T) {
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+		TagGoroutine:  true,
+	}
+	_, err := loggers.NewFileLoggerTemplate(levels.Info, "{time:}", fileOptions)
+	assert.Error(t, err)
+
+	fileLogger, err := loggers.NewFileLoggerTemplate(levels.Info, "{level} {message} {fields}", fileOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileLogger.Log(levels.Info, "Message 1")
+	fileLogger.LogF(levels.Error, "Message %d", 2)
+	fileLogger.LogKV(levels.Warning, "Message 3", loggers.F("user", 42))
+	fileLogger.Close()
+
+	content := removeLogFiles(t, fileOptions)
+	// The loggers with a formatter add the goroutine id as field.
+	assert.Regexp(t, regexp.MustCompile(`^INFO Message 1 goid=\d+\nERROR Message 2 goid=\d+\nWARNING Message 3 goid=\d+ user=42\n$`), content)
+}
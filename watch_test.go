@@ -0,0 +1,242 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+const watchInterval = 5 * time.Millisecond
+
+func writeWatchedConfig(t *testing.T, path, content string) {
+	// The file is replaced by rename, so the watcher never reads a partial file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchConfigFileNoLostLines(t *testing.T) {
+	dir := t.TempDir()
+	logDir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	appConfig := func(level, format string) string {
+		return fmt.Sprintf("  app:\n    type: file\n    level: %s\n    format: %s\n    file:\n      directory: %s\n      prefix: app\n      extension: .log\n", level, format, logDir)
+	}
+	writeWatchedConfig(t, path, "disable_default: true\nloggers:\n"+appConfig("info", "text"))
+
+	l := logger.New()
+	watcher, err := l.WatchConfigFile(path, watchInterval)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+	assert.Equal(t, levels.Info, l.Get("app").GetLevel())
+
+	var counter atomic.Int64
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					l.Info("Message " + strconv.FormatInt(counter.Add(1), 10))
+				}
+			}
+		}()
+	}
+
+	steps := []struct {
+		content string
+		check   func() bool
+	}{
+		{"disable_default: true\nloggers:\n" + appConfig("debug", "json"), func() bool {
+			return l.Get("app").GetLevel() == levels.Debug
+		}},
+		{"disable_default: true\nloggers:\n" + appConfig("debug", "logfmt") + "  extra:\n    type: console\n    level: fatal\n", func() bool {
+			return l.Get("extra") != nil
+		}},
+		{"disable_default: true\nloggers:\n" + appConfig("info", "\"{level} {message}\""), func() bool {
+			return l.Get("extra") == nil && l.Get("app").GetLevel() == levels.Info
+		}},
+	}
+	// Each configuration logs some messages before it is changed.
+	logSome := func() {
+		logged := counter.Load()
+		assert.Eventually(t, func() bool {
+			return counter.Load() > logged+100
+		}, 5*time.Second, time.Millisecond)
+	}
+	for _, step := range steps {
+		logSome()
+		writeWatchedConfig(t, path, step.content)
+		assert.Eventually(t, step.check, 5*time.Second, watchInterval)
+	}
+	logSome()
+	close(stop)
+	wg.Wait()
+	assert.NoError(t, watcher.Close())
+	assert.NoError(t, l.Shutdown(context.Background()))
+	assert.False(t, l.DefaultLogger().(*loggers.ConsoleLogger).IsLogAllowed(levels.Fatal))
+
+	seen := make(map[int64]int)
+	pattern := regexp.MustCompile(`Message (\d+)\b`)
+	content := readDirContent(t, logDir)
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		match := pattern.FindStringSubmatch(line)
+		if !assert.NotNil(t, match, line) {
+			continue
+		}
+		n, _ := strconv.ParseInt(match[1], 10, 64)
+		seen[n]++
+	}
+	total := counter.Load()
+	assert.Len(t, seen, int(total))
+	for n := int64(1); n <= total; n++ {
+		assert.Equal(t, 1, seen[n], "Message %d", n)
+	}
+	for _, format := range []string{`"msg":"Message `, "msg=\"Message ", "INFO Message "} {
+		assert.Contains(t, content, format)
+	}
+}
+
+// Run with -race to check that the levels are changed while other goroutines are logging.
+func TestWatchConfigFileLevelChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	config := func(defaultLevel, level, format string) string {
+		return fmt.Sprintf("level: %s\nloggers:\n  json:\n    type: console\n    level: %s\n    format: %s\n", defaultLevel, level, format)
+	}
+	writeWatchedConfig(t, path, config("fatal", "fatal", "json"))
+
+	l := logger.New()
+	watcher, err := l.WatchConfigFile(path, watchInterval)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+	jsonLogger := l.Get("json")
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				l.Info("Skipped message")
+			}
+		}
+	}()
+
+	// The logger with changed level only is kept.
+	writeWatchedConfig(t, path, config("error", "error", "json"))
+	assert.Eventually(t, func() bool {
+		return jsonLogger.GetLevel() == levels.Error && l.DefaultLogger().GetLevel() == levels.Error
+	}, 5*time.Second, watchInterval)
+	assert.Same(t, jsonLogger, l.Get("json"))
+
+	t.Setenv(logger.LevelEnvName("json"), "fatal")
+	t.Setenv(logger.LevelEnv, "fatal")
+	assert.NoError(t, l.SetLevelsFromEnv())
+	assert.Equal(t, levels.Fatal, jsonLogger.GetLevel())
+	assert.Equal(t, levels.Fatal, l.DefaultLogger().GetLevel())
+
+	// The logger with changed format is built again.
+	writeWatchedConfig(t, path, config("error", "error", "logfmt"))
+	assert.Eventually(t, func() bool {
+		return l.Get("json") != jsonLogger
+	}, 5*time.Second, watchInterval)
+	assert.Equal(t, levels.Error, l.Get("json").GetLevel())
+	close(stop)
+	wg.Wait()
+}
+
+func TestWatchConfigFileLevelRemoved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	writeWatchedConfig(t, path, "level: error\nloggers:\n  console:\n    type: console\n    level: error\n")
+
+	l := logger.New()
+	watcher, err := l.WatchConfigFile(path, watchInterval)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+	assert.Equal(t, levels.Error, l.DefaultLogger().GetLevel())
+	consoleLogger := l.Get("console")
+	assert.Equal(t, levels.Error, consoleLogger.GetLevel())
+
+	// The removed levels are reset to info.
+	writeWatchedConfig(t, path, "loggers:\n  console:\n    type: console\n")
+	assert.Eventually(t, func() bool {
+		return consoleLogger.GetLevel() == levels.Info && l.DefaultLogger().GetLevel() == levels.Info
+	}, 5*time.Second, watchInterval)
+	assert.Same(t, consoleLogger, l.Get("console"))
+}
+
+func TestWatchConfigFileInvalidChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.json")
+	writeWatchedConfig(t, path, `{"loggers": {"console": {"type": "console", "level": "warning"}}}`)
+
+	l, r := newRecordingMultiLogger(t)
+	watcher, err := l.WatchConfigFile(path, watchInterval)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+	console := l.Get("console")
+	console.Stop()
+
+	writeWatchedConfig(t, path, `{"loggers": {"console": {"type": "console", "level": "loud"}}}`)
+	assert.Eventually(t, func() bool {
+		return len(r.Messages()) > 0
+	}, 5*time.Second, watchInterval)
+	assert.Contains(t, r.Messages()[0], "Reloading logger configuration failed")
+	assert.Contains(t, r.Messages()[0], `Logger \"console\"`)
+	assert.Same(t, console, l.Get("console"))
+
+	// The error is reported only once for the same content.
+	time.Sleep(10 * watchInterval)
+	assert.Len(t, r.Messages(), 1)
+
+	_, err = l.WatchConfigFile(filepath.Join(t.TempDir(), "missing.json"), watchInterval)
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+/* Copyright 2024 Take Control - Software & Infrastructure
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package go_multi_log
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takecontrolsoft/go_multi_log/logger"
+	"github.com/takecontrolsoft/go_multi_log/logger/levels"
+	"github.com/takecontrolsoft/go_multi_log/logger/loggers"
+)
+
+func TestWithBoundFields(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	reqLog := l.With("request_id", 7, "tenant", "acme")
+
+	reqLog.Info("Request started")
+	reqLog.WarningF("Retry %d", 2)
+	reqLog.ErrorKV("Request failed", "status", 500)
+	l.Info("Parent message")
+
+	assert.Equal(t, []string{
+		"Request started request_id=7 tenant=acme",
+		"Retry 2 request_id=7 tenant=acme",
+		"Request failed request_id=7 tenant=acme status=500",
+		"Parent message",
+	}, r.Messages())
+}
+
+func TestWithNestedChildren(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	reqLog := l.With("request_id", 7)
+	first := reqLog.With(loggers.F("step", 1))
+	second := reqLog.With("step", 2)
+
+	first.Info("First")
+	second.Info("Second")
+	reqLog.Info("Request")
+	assert.Same(t, reqLog, reqLog.With())
+
+	assert.Equal(t, []string{
+		"First request_id=7 step=1",
+		"Second request_id=7 step=2",
+		"Request request_id=7",
+	}, r.Messages())
+
+	// Children share the registry of their parent.
+	other := newRecordingLogger()
+	assert.NoError(t, first.Register("other", other))
+	l.Info("Registered by child")
+	assert.Equal(t, []string{"Registered by child"}, other.Messages())
+}
+
+func TestWithAllLoggerTypes(t *testing.T) {
+	fileOptions := loggers.FileOptions{
+		Directory:     "./",
+		FilePrefix:    generateRandomString(5),
+		FileExtension: ".log",
+	}
+	var buf bytes.Buffer
+	l := logger.New()
+	l.Register("file", loggers.NewFileLogger(levels.Info, "", fileOptions))
+	l.Register("json", loggers.NewJSONLogger(levels.Info, &buf))
+	reqLog := l.With("request_id", 7)
+
+	content := readConsole(func() {
+		reqLog.Info("Request started")
+	})
+	assert.Contains(t, content, "INFO: [Request started] request_id=7")
+
+	l.Unregister("file")
+	content = removeLogFiles(t, fileOptions)
+	assert.Contains(t, content, "INFO: [Request started] request_id=7")
+
+	lines := decodeJSONLines(t, buf.String())
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "Request started", lines[0]["msg"])
+	assert.Equal(t, float64(7), lines[0]["request_id"])
+}
+
+func TestWithError(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	var jsonBuf, logfmtBuf, slogBuf bytes.Buffer
+	l.Register("json", loggers.NewJSONLogger(levels.Info, &jsonBuf))
+	l.Register("logfmt", loggers.NewLogfmtLogger(levels.Info, &logfmtBuf))
+	l.Register("slog", loggers.NewSlogLogger(levels.Info, slog.NewJSONHandler(&slogBuf, nil)))
+
+	err := errors.New("Disk is full")
+	l.With("request_id", 7).Error(err)
+	l.SetCallerMode(logger.CallerFile)
+	l.Error(err)
+
+	// The error is kept for the loggers with fields support.
+	entries := decodeJSONLines(t, jsonBuf.String())
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "Disk is full", entry["msg"])
+		assert.Equal(t, "Disk is full", entry["error"])
+	}
+	entries = decodeJSONLines(t, slogBuf.String())
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "Disk is full", entry["error"])
+	}
+	assert.Equal(t, 2, strings.Count(logfmtBuf.String(), `error="Disk is full"`), logfmtBuf.String())
+	// The other loggers print the error only as the message.
+	if messages := r.Messages(); assert.Len(t, messages, 2) {
+		assert.Equal(t, "Disk is full request_id=7", messages[0])
+		assert.NotContains(t, messages[1], "error=")
+	}
+}
+
+func TestWithPackageLevel(t *testing.T) {
+	content := readConsole(func() {
+		logger.With("request_id", 7).Info("Request started")
+	})
+	assert.Contains(t, content, "INFO: [Request started] request_id=7")
+}
+
+func TestWithSlogHandler(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	slog.New(l.With("request_id", 7).SlogHandler()).Info("Slog message", "user", 42)
+	assert.Equal(t, []string{"Slog message request_id=7 user=42"}, r.Messages())
+}
+
+func TestWithFatal(t *testing.T) {
+	l, r := newRecordingMultiLogger(t)
+	assert.PanicsWithValue(t, "Fatal message", func() {
+		l.With("request_id", 7).Fatal("Fatal message")
+	})
+	assert.Equal(t, []string{"Fatal message request_id=7"}, r.Messages())
+}